@@ -1,79 +1,608 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"maps"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
-	_ "github.com/joho/godotenv/autoload"
-	"github.com/rodrigoaraujo46/assert"
+	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Host     string
-	Port     string
-	Redis    Redis
-	Postgres Postgres
-	Gothic   Gothic
-	MovieAPI MovieAPI
+	Host            string          `yaml:"host"`
+	Port            string          `yaml:"port"`
+	ShutdownTimeout time.Duration   `yaml:"shutdown_timeout"`
+	Stores          Stores          `yaml:"stores"`
+	CORS            CORS            `yaml:"cors"`
+	Cookie          Cookie          `yaml:"cookie"`
+	Security        Security        `yaml:"security"`
+	RateLimits      RateLimits      `yaml:"rate_limits"`
+	Redis           Redis           `yaml:"redis"`
+	Postgres        Postgres        `yaml:"postgres"`
+	Gothic          Gothic          `yaml:"gothic"`
+	MovieAPI        MovieAPI        `yaml:"movie_api"`
+	Events          Events          `yaml:"events"`
+	Webhooks        Webhooks        `yaml:"webhooks"`
+	Recommendations Recommendations `yaml:"recommendations"`
+	Taste           Taste           `yaml:"taste"`
+	Ratings         Ratings         `yaml:"ratings"`
+	Trending        Trending        `yaml:"trending"`
+	AdminEmails     []string        `yaml:"admin_emails"`
+	TrustedProxies  []string        `yaml:"trusted_proxies"`
+}
+
+type Stores struct {
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+type CORS struct {
+	AllowOrigins []string `yaml:"allow_origins"`
+}
+
+type Cookie struct {
+	Domain   string `yaml:"domain"`
+	Path     string `yaml:"path"`
+	Secure   bool   `yaml:"secure"`
+	SameSite string `yaml:"same_site"`
+	Prefix   string `yaml:"prefix"`
+}
+
+type Security struct {
+	ContentSecurityPolicy string `yaml:"content_security_policy"`
+	HSTSMaxAge            int    `yaml:"hsts_max_age"`
+	HSTSPreload           bool   `yaml:"hsts_preload"`
+	FrameOptions          string `yaml:"frame_options"`
+	ReferrerPolicy        string `yaml:"referrer_policy"`
+}
+
+type RateLimits struct {
+	Search  RateLimit `yaml:"search"`
+	Reviews RateLimit `yaml:"reviews"`
+	Auth    RateLimit `yaml:"auth"`
+}
+
+// RateLimit allows Limit requests per client in any sliding Window.
+type RateLimit struct {
+	Limit  int           `yaml:"limit"`
+	Window time.Duration `yaml:"window"`
 }
 
 type Redis struct {
-	Address string
+	Address string `yaml:"address"`
 }
 
 type Postgres struct {
-	Address string
+	Address string `yaml:"address"`
 }
 
 type MovieAPI struct {
-	Token string
+	Token            string        `yaml:"token"`
+	BaseURL          string        `yaml:"base_url"`
+	Timeout          time.Duration `yaml:"timeout"`
+	MaxRetries       int           `yaml:"max_retries"`
+	BreakerThreshold int           `yaml:"breaker_threshold"`
+	BreakerCooldown  time.Duration `yaml:"breaker_cooldown"`
+}
+
+// Events configures the server-sent event streams. History is how many
+// events per topic are kept for clients reconnecting with Last-Event-ID.
+type Events struct {
+	Heartbeat               time.Duration `yaml:"heartbeat"`
+	History                 int           `yaml:"history"`
+	HistoryTTL              time.Duration `yaml:"history_ttl"`
+	MaxConnections          int           `yaml:"max_connections"`
+	MaxConnectionsPerClient int           `yaml:"max_connections_per_client"`
+}
+
+// Webhooks configures the delivery of webhook events. Every Interval up to
+// BatchSize due deliveries are sent, each failed one being retried with
+// exponential backoff until it had MaxAttempts. Delivered events are kept
+// for Retention.
+type Webhooks struct {
+	Interval    time.Duration `yaml:"interval"`
+	Timeout     time.Duration `yaml:"timeout"`
+	MaxAttempts int           `yaml:"max_attempts"`
+	BatchSize   int           `yaml:"batch_size"`
+	Retention   time.Duration `yaml:"retention"`
+}
+
+// Recommendations configures the recommendations computed from local
+// ratings every Interval. Two movies are only compared once MinOverlap users
+// rated both, each movie keeps its Neighbors most similar ones, and every
+// user keeps PerUser recommendations.
+type Recommendations struct {
+	Interval   time.Duration `yaml:"interval"`
+	MinOverlap int           `yaml:"min_overlap"`
+	Neighbors  int           `yaml:"neighbors"`
+	PerUser    int           `yaml:"per_user"`
+}
+
+// Taste configures how users' tastes are compared. Users need MinOverlap
+// movies in common to get a score, and comparisons are cached for CacheTTL
+// unless either user's reviews change. Similar lists are only cached for
+// SimilarTTL, as they also change with the reviews of users not yet listed.
+type Taste struct {
+	MinOverlap   int           `yaml:"min_overlap"`
+	SimilarLimit int           `yaml:"similar_limit"`
+	CacheTTL     time.Duration `yaml:"cache_ttl"`
+	SimilarTTL   time.Duration `yaml:"similar_ttl"`
+}
+
+// Ratings configures the weighted rating top rated charts rank movies by,
+// which counts every movie as having MinVotes more reviews rated GlobalMean.
+// A zero GlobalMean uses the mean of every rating in the chart's window.
+type Ratings struct {
+	MinVotes   int     `yaml:"min_votes"`
+	GlobalMean float64 `yaml:"global_mean"`
+}
+
+// Trending configures the ranking of the movies trending on FlickMeter,
+// refreshed every Interval and keeping Size movies per period.
+type Trending struct {
+	Interval time.Duration `yaml:"interval"`
+	Size     int           `yaml:"size"`
 }
 
 type Gothic struct {
-	Providers      map[string]oAuthProvider
-	CookieStoreKey string
+	Providers      map[string]oAuthProvider `yaml:"providers"`
+	CookieStoreKey string                   `yaml:"cookie_store_key"`
 }
 
 type oAuthProvider struct {
-	Client   string
-	Secret   string
-	Callback string
+	Client   string `yaml:"client"`
+	Secret   string `yaml:"secret"`
+	Callback string `yaml:"callback"`
 }
 
-func MustLoadConfig() Config {
-	assert.NoError(godotenv.Load(), "Couldn't open .env files")
-
+// Default returns the configuration every other source is layered on top of.
+func Default() Config {
 	return Config{
-		Host:     mustLoadEnv("HOST"),
-		Port:     mustLoadEnv("PORT"),
-		Redis:    Redis{Address: mustLoadEnv("REDIS_ADDR")},
-		Postgres: Postgres{Address: mustLoadEnv("POSTGRES_ADDR")},
-		Gothic:   Gothic{CookieStoreKey: mustLoadEnv("COOKIE_STORE_KEY"), Providers: mustLoadProviders()},
-		MovieAPI: MovieAPI{Token: mustLoadEnv("MOVIE_DB_TOKEN")},
+		Host:            "0.0.0.0",
+		Port:            "1323",
+		ShutdownTimeout: 10 * time.Second,
+		Stores:          Stores{Timeout: time.Second},
+		CORS:            CORS{AllowOrigins: []string{"http://web:5173", "http://web:4173"}},
+		Cookie:          Cookie{Path: "/", Secure: true, SameSite: "strict"},
+		Security: Security{
+			ContentSecurityPolicy: "default-src 'none'; frame-ancestors 'none'",
+			HSTSMaxAge:            31536000,
+			FrameOptions:          "DENY",
+			ReferrerPolicy:        "strict-origin-when-cross-origin",
+		},
+		RateLimits: RateLimits{
+			Search:  RateLimit{Limit: 30, Window: time.Minute},
+			Reviews: RateLimit{Limit: 10, Window: time.Minute},
+			Auth:    RateLimit{Limit: 10, Window: time.Minute},
+		},
+		MovieAPI: MovieAPI{
+			BaseURL:          "https://api.themoviedb.org/3",
+			Timeout:          time.Second,
+			MaxRetries:       2,
+			BreakerThreshold: 5,
+			BreakerCooldown:  30 * time.Second,
+		},
+		Events: Events{
+			Heartbeat:               15 * time.Second,
+			History:                 100,
+			HistoryTTL:              time.Hour,
+			MaxConnections:          1000,
+			MaxConnectionsPerClient: 5,
+		},
+		Webhooks: Webhooks{
+			Interval:    5 * time.Second,
+			Timeout:     5 * time.Second,
+			MaxAttempts: 8,
+			BatchSize:   50,
+			Retention:   7 * 24 * time.Hour,
+		},
+		Recommendations: Recommendations{
+			Interval:   6 * time.Hour,
+			MinOverlap: 3,
+			Neighbors:  50,
+			PerUser:    100,
+		},
+		Taste: Taste{
+			MinOverlap:   5,
+			SimilarLimit: 20,
+			CacheTTL:     24 * time.Hour,
+			SimilarTTL:   10 * time.Minute,
+		},
+		Ratings:  Ratings{MinVotes: 5},
+		Trending: Trending{Interval: 10 * time.Minute, Size: 100},
+		Gothic:   Gothic{Providers: map[string]oAuthProvider{}},
+		TrustedProxies: []string{
+			"127.0.0.0/8", "::1/128", "10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "fc00::/7",
+		},
+	}
+}
+
+// Load builds the configuration from, in increasing order of precedence, the
+// defaults, the YAML file named by CONFIG_FILE (config.yaml if unset), an
+// optional .env file and the process environment.
+// Every missing or invalid key is reported in the returned error.
+func Load() (Config, error) {
+	c := Default()
+
+	if err := loadFile(&c); err != nil {
+		return c, err
+	}
+
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return c, fmt.Errorf("config: failed to load .env: %w", err)
+	}
+
+	l := &loader{}
+	l.str(&c.Host, "HOST")
+	l.str(&c.Port, "PORT")
+	l.duration(&c.ShutdownTimeout, "SHUTDOWN_TIMEOUT")
+	l.duration(&c.Stores.Timeout, "STORE_TIMEOUT")
+	l.list(&c.CORS.AllowOrigins, "CORS_ALLOW_ORIGINS")
+	l.str(&c.Cookie.Domain, "COOKIE_DOMAIN")
+	l.str(&c.Cookie.Path, "COOKIE_PATH")
+	l.boolean(&c.Cookie.Secure, "COOKIE_SECURE")
+	l.str(&c.Cookie.SameSite, "COOKIE_SAME_SITE")
+	l.str(&c.Cookie.Prefix, "COOKIE_PREFIX")
+	l.str(&c.Security.ContentSecurityPolicy, "SECURITY_CSP")
+	l.integer(&c.Security.HSTSMaxAge, "SECURITY_HSTS_MAX_AGE")
+	l.boolean(&c.Security.HSTSPreload, "SECURITY_HSTS_PRELOAD")
+	l.str(&c.Security.FrameOptions, "SECURITY_FRAME_OPTIONS")
+	l.str(&c.Security.ReferrerPolicy, "SECURITY_REFERRER_POLICY")
+	l.rate(&c.RateLimits.Search, "RATE_LIMIT_SEARCH")
+	l.rate(&c.RateLimits.Reviews, "RATE_LIMIT_REVIEWS")
+	l.rate(&c.RateLimits.Auth, "RATE_LIMIT_AUTH")
+	l.str(&c.Redis.Address, "REDIS_ADDR")
+	l.str(&c.Postgres.Address, "POSTGRES_ADDR")
+	l.str(&c.Gothic.CookieStoreKey, "COOKIE_STORE_KEY")
+	l.str(&c.MovieAPI.Token, "MOVIE_DB_TOKEN")
+	l.str(&c.MovieAPI.BaseURL, "MOVIE_DB_BASE_URL")
+	l.duration(&c.MovieAPI.Timeout, "MOVIE_DB_TIMEOUT")
+	l.integer(&c.MovieAPI.MaxRetries, "MOVIE_DB_MAX_RETRIES")
+	l.integer(&c.MovieAPI.BreakerThreshold, "MOVIE_DB_BREAKER_THRESHOLD")
+	l.duration(&c.MovieAPI.BreakerCooldown, "MOVIE_DB_BREAKER_COOLDOWN")
+	l.duration(&c.Events.Heartbeat, "EVENTS_HEARTBEAT")
+	l.integer(&c.Events.History, "EVENTS_HISTORY")
+	l.duration(&c.Events.HistoryTTL, "EVENTS_HISTORY_TTL")
+	l.integer(&c.Events.MaxConnections, "EVENTS_MAX_CONNECTIONS")
+	l.integer(&c.Events.MaxConnectionsPerClient, "EVENTS_MAX_CONNECTIONS_PER_CLIENT")
+	l.duration(&c.Webhooks.Interval, "WEBHOOKS_INTERVAL")
+	l.duration(&c.Webhooks.Timeout, "WEBHOOKS_TIMEOUT")
+	l.integer(&c.Webhooks.MaxAttempts, "WEBHOOKS_MAX_ATTEMPTS")
+	l.integer(&c.Webhooks.BatchSize, "WEBHOOKS_BATCH_SIZE")
+	l.duration(&c.Webhooks.Retention, "WEBHOOKS_RETENTION")
+	l.integer(&c.Taste.MinOverlap, "TASTE_MIN_OVERLAP")
+	l.integer(&c.Taste.SimilarLimit, "TASTE_SIMILAR_LIMIT")
+	l.duration(&c.Taste.CacheTTL, "TASTE_CACHE_TTL")
+	l.duration(&c.Taste.SimilarTTL, "TASTE_SIMILAR_TTL")
+	l.integer(&c.Ratings.MinVotes, "RATINGS_MIN_VOTES")
+	l.float(&c.Ratings.GlobalMean, "RATINGS_GLOBAL_MEAN")
+	l.duration(&c.Trending.Interval, "TRENDING_INTERVAL")
+	l.integer(&c.Trending.Size, "TRENDING_SIZE")
+	l.list(&c.AdminEmails, "ADMIN_EMAILS")
+	l.list(&c.TrustedProxies, "TRUSTED_PROXIES")
+	l.duration(&c.Recommendations.Interval, "RECOMMENDATIONS_INTERVAL")
+	l.integer(&c.Recommendations.MinOverlap, "RECOMMENDATIONS_MIN_OVERLAP")
+	l.integer(&c.Recommendations.Neighbors, "RECOMMENDATIONS_NEIGHBORS")
+	l.integer(&c.Recommendations.PerUser, "RECOMMENDATIONS_PER_USER")
+	l.providers(c.Gothic.Providers, "PROVIDERS")
+
+	for name, p := range c.Gothic.Providers {
+		if p.Callback == "" {
+			p.Callback = fmt.Sprintf("http://localhost:5173/api/users/auth/%s/callback", name)
+			c.Gothic.Providers[name] = p
+		}
+	}
+
+	return c, errors.Join(append(l.errs, c.validate()...)...)
+}
+
+func loadFile(c *Config) error {
+	path, explicit := os.LookupEnv("CONFIG_FILE")
+	if !explicit {
+		path = "config.yaml"
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) && !explicit {
+			return nil
+		}
+		return fmt.Errorf("config: failed to read %s: %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(b, c); err != nil {
+		return fmt.Errorf("config: failed to parse %s: %w", path, err)
+	}
+
+	return nil
+}
+
+type loader struct {
+	errs []error
+}
+
+func (l *loader) str(dst *string, name string) {
+	if value, found := os.LookupEnv(name); found {
+		*dst = value
+	}
+}
+
+func (l *loader) duration(dst *time.Duration, name string) {
+	value, found := os.LookupEnv(name)
+	if !found {
+		return
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("%s: invalid duration %q", name, value))
+		return
+	}
+	*dst = d
+}
+
+func (l *loader) integer(dst *int, name string) {
+	value, found := os.LookupEnv(name)
+	if !found {
+		return
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("%s: invalid integer %q", name, value))
+		return
+	}
+	*dst = i
+}
+
+func (l *loader) float(dst *float64, name string) {
+	value, found := os.LookupEnv(name)
+	if !found {
+		return
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("%s: invalid number %q", name, value))
+		return
+	}
+	*dst = f
+}
+
+func (l *loader) boolean(dst *bool, name string) {
+	value, found := os.LookupEnv(name)
+	if !found {
+		return
 	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("%s: invalid boolean %q", name, value))
+		return
+	}
+	*dst = b
 }
 
-func mustLoadEnv(name string) string {
-	port, found := os.LookupEnv(name)
-	assert.Assert(found, fmt.Sprintf("No %s in .env", name))
+func (l *loader) list(dst *[]string, name string) {
+	value, found := os.LookupEnv(name)
+	if !found {
+		return
+	}
 
-	return port
+	*dst = nil
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*dst = append(*dst, item)
+		}
+	}
 }
 
-func mustLoadProviders() map[string]oAuthProvider {
-	names := strings.Split(mustLoadEnv("PROVIDERS"), ",")
-	configs := make(map[string]oAuthProvider, len(names))
+// rate parses limits written as "<limit>/<window>", e.g. "30/1m".
+func (l *loader) rate(dst *RateLimit, name string) {
+	value, found := os.LookupEnv(name)
+	if !found {
+		return
+	}
+
+	limit, window, _ := strings.Cut(value, "/")
+	n, err1 := strconv.Atoi(limit)
+	d, err2 := time.ParseDuration(window)
+	if err := errors.Join(err1, err2); err != nil {
+		l.errs = append(l.errs, fmt.Errorf("%s: invalid rate limit %q", name, value))
+		return
+	}
+	*dst = RateLimit{Limit: n, Window: d}
+}
+
+func (l *loader) providers(dst map[string]oAuthProvider, name string) {
+	var names []string
+	l.list(&names, name)
 
 	for _, name := range names {
-		config := oAuthProvider{
-			Client:   mustLoadEnv(fmt.Sprintf("%s_CLIENT", strings.ToUpper(name))),
-			Secret:   mustLoadEnv(fmt.Sprintf("%s_SECRET", strings.ToUpper(name))),
-			Callback: fmt.Sprintf("http://localhost:5173/api/users/auth/%s/callback", name),
+		prefix := strings.ToUpper(name)
+
+		p := dst[name]
+		l.str(&p.Client, prefix+"_CLIENT")
+		l.str(&p.Secret, prefix+"_SECRET")
+		l.str(&p.Callback, prefix+"_CALLBACK")
+		dst[name] = p
+	}
+}
+
+func (c Config) validate() (errs []error) {
+	required := func(value, key string) {
+		if value == "" {
+			errs = append(errs, fmt.Errorf("%s: required", key))
+		}
+	}
+	positive := func(d time.Duration, key string) {
+		if d <= 0 {
+			errs = append(errs, fmt.Errorf("%s: must be positive", key))
+		}
+	}
+
+	required(c.Host, "HOST")
+	required(c.Port, "PORT")
+	positive(c.ShutdownTimeout, "SHUTDOWN_TIMEOUT")
+	positive(c.Stores.Timeout, "STORE_TIMEOUT")
+	required(c.Redis.Address, "REDIS_ADDR")
+	required(c.Postgres.Address, "POSTGRES_ADDR")
+	required(c.Gothic.CookieStoreKey, "COOKIE_STORE_KEY")
+	required(c.MovieAPI.Token, "MOVIE_DB_TOKEN")
+	positive(c.MovieAPI.Timeout, "MOVIE_DB_TIMEOUT")
+	positive(c.MovieAPI.BreakerCooldown, "MOVIE_DB_BREAKER_COOLDOWN")
+
+	if c.MovieAPI.MaxRetries < 0 {
+		errs = append(errs, errors.New("MOVIE_DB_MAX_RETRIES: must not be negative"))
+	}
+	if c.MovieAPI.BreakerThreshold <= 0 {
+		errs = append(errs, errors.New("MOVIE_DB_BREAKER_THRESHOLD: must be positive"))
+	}
+
+	if _, err := strconv.ParseUint(c.Port, 10, 16); c.Port != "" && err != nil {
+		errs = append(errs, fmt.Errorf("PORT: invalid port %q", c.Port))
+	}
+
+	if u, err := url.Parse(c.MovieAPI.BaseURL); err != nil || u.Scheme == "" || u.Host == "" {
+		errs = append(errs, fmt.Errorf("MOVIE_DB_BASE_URL: invalid url %q", c.MovieAPI.BaseURL))
+	}
+
+	for _, origin := range c.CORS.AllowOrigins {
+		if u, err := url.Parse(origin); origin != "*" && (err != nil || u.Scheme == "" || u.Host == "") {
+			errs = append(errs, fmt.Errorf("CORS_ALLOW_ORIGINS: invalid origin %q", origin))
+		}
+	}
+
+	for _, cidr := range c.TrustedProxies {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			errs = append(errs, fmt.Errorf("TRUSTED_PROXIES: invalid range %q", cidr))
+		}
+	}
+
+	if sameSite, err := parseSameSite(c.Cookie.SameSite); err != nil {
+		errs = append(errs, fmt.Errorf("COOKIE_SAME_SITE: %w", err))
+	} else if sameSite == http.SameSiteNoneMode && !c.Cookie.Secure {
+		errs = append(errs, errors.New("COOKIE_SAME_SITE: none requires COOKIE_SECURE"))
+	}
+
+	switch c.Cookie.Prefix {
+	case "":
+	case "__Secure-":
+		if !c.Cookie.Secure {
+			errs = append(errs, errors.New("COOKIE_PREFIX: __Secure- requires COOKIE_SECURE"))
+		}
+	case "__Host-":
+		if !c.Cookie.Secure || c.Cookie.Path != "/" || c.Cookie.Domain != "" {
+			errs = append(errs, errors.New("COOKIE_PREFIX: __Host- requires COOKIE_SECURE, COOKIE_PATH=/ and no COOKIE_DOMAIN"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("COOKIE_PREFIX: invalid prefix %q", c.Cookie.Prefix))
+	}
+
+	rate := func(r RateLimit, key string) {
+		if r.Limit <= 0 || r.Window <= 0 {
+			errs = append(errs, fmt.Errorf("%s: limit and window must be positive", key))
+		}
+	}
+	rate(c.RateLimits.Search, "RATE_LIMIT_SEARCH")
+	rate(c.RateLimits.Reviews, "RATE_LIMIT_REVIEWS")
+	rate(c.RateLimits.Auth, "RATE_LIMIT_AUTH")
+
+	positive(c.Events.Heartbeat, "EVENTS_HEARTBEAT")
+	positive(c.Events.HistoryTTL, "EVENTS_HISTORY_TTL")
+	if c.Events.History < 0 {
+		errs = append(errs, errors.New("EVENTS_HISTORY: must not be negative"))
+	}
+	if c.Events.MaxConnections <= 0 || c.Events.MaxConnectionsPerClient <= 0 {
+		errs = append(errs, errors.New("EVENTS_MAX_CONNECTIONS and EVENTS_MAX_CONNECTIONS_PER_CLIENT: must be positive"))
+	}
+
+	positive(c.Webhooks.Interval, "WEBHOOKS_INTERVAL")
+	positive(c.Webhooks.Timeout, "WEBHOOKS_TIMEOUT")
+	positive(c.Webhooks.Retention, "WEBHOOKS_RETENTION")
+	if c.Webhooks.MaxAttempts <= 0 || c.Webhooks.BatchSize <= 0 {
+		errs = append(errs, errors.New("WEBHOOKS_MAX_ATTEMPTS and WEBHOOKS_BATCH_SIZE: must be positive"))
+	}
+
+	positive(c.Recommendations.Interval, "RECOMMENDATIONS_INTERVAL")
+	if c.Recommendations.MinOverlap <= 0 || c.Recommendations.Neighbors <= 0 || c.Recommendations.PerUser <= 0 {
+		errs = append(errs, errors.New("RECOMMENDATIONS_MIN_OVERLAP, RECOMMENDATIONS_NEIGHBORS and RECOMMENDATIONS_PER_USER: must be positive"))
+	}
+
+	positive(c.Taste.CacheTTL, "TASTE_CACHE_TTL")
+	positive(c.Taste.SimilarTTL, "TASTE_SIMILAR_TTL")
+	if c.Taste.MinOverlap < 2 {
+		errs = append(errs, errors.New("TASTE_MIN_OVERLAP: must be at least 2"))
+	}
+	if c.Taste.SimilarLimit <= 0 {
+		errs = append(errs, errors.New("TASTE_SIMILAR_LIMIT: must be positive"))
+	}
+
+	if c.Ratings.MinVotes < 0 {
+		errs = append(errs, errors.New("RATINGS_MIN_VOTES: must not be negative"))
+	}
+	if c.Ratings.GlobalMean < 0 || c.Ratings.GlobalMean > 10 {
+		errs = append(errs, errors.New("RATINGS_GLOBAL_MEAN: must be between 0 and 10"))
+	}
+
+	positive(c.Trending.Interval, "TRENDING_INTERVAL")
+	if c.Trending.Size <= 0 {
+		errs = append(errs, errors.New("TRENDING_SIZE: must be positive"))
+	}
+
+	if c.Security.HSTSMaxAge < 0 {
+		errs = append(errs, errors.New("SECURITY_HSTS_MAX_AGE: must not be negative"))
+	}
+
+	if len(c.Gothic.Providers) == 0 {
+		errs = append(errs, errors.New("PROVIDERS: required"))
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Gothic.Providers)) {
+		p, prefix := c.Gothic.Providers[name], strings.ToUpper(name)
+		required(p.Client, prefix+"_CLIENT")
+		required(p.Secret, prefix+"_SECRET")
+	}
+
+	return errs
+}
+
+// TrustedProxyRanges returns the validated ranges of TrustedProxies.
+func (c Config) TrustedProxyRanges() []*net.IPNet {
+	ranges := make([]*net.IPNet, 0, len(c.TrustedProxies))
+	for _, cidr := range c.TrustedProxies {
+		if _, ipNet, err := net.ParseCIDR(cidr); err == nil {
+			ranges = append(ranges, ipNet)
 		}
-		configs[name] = config
 	}
+	return ranges
+}
+
+// SameSiteMode returns the validated SameSite attribute of the cookie.
+func (c Cookie) SameSiteMode() http.SameSite {
+	mode, _ := parseSameSite(c.SameSite)
+	return mode
+}
 
-	return configs
+func parseSameSite(s string) (http.SameSite, error) {
+	switch strings.ToLower(s) {
+	case "", "default":
+		return http.SameSiteDefaultMode, nil
+	case "lax":
+		return http.SameSiteLaxMode, nil
+	case "strict":
+		return http.SameSiteStrictMode, nil
+	case "none":
+		return http.SameSiteNoneMode, nil
+	default:
+		return 0, fmt.Errorf("invalid same site mode %q", s)
+	}
 }
@@ -0,0 +1,220 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// requiredEnv is the smallest environment Load accepts.
+var requiredEnv = map[string]string{
+	"REDIS_ADDR":       "localhost:6379",
+	"POSTGRES_ADDR":    "postgres://localhost/flickmeter",
+	"COOKIE_STORE_KEY": "key",
+	"MOVIE_DB_TOKEN":   "token",
+	"PROVIDERS":        "google",
+	"GOOGLE_CLIENT":    "client",
+	"GOOGLE_SECRET":    "secret",
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		env     map[string]string
+		check   func(t *testing.T, c Config)
+		wantErr []string
+	}{
+		{
+			name: "defaults",
+			check: func(t *testing.T, c Config) {
+				if c.Port != "1323" || c.Stores.Timeout != time.Second {
+					t.Errorf("Port, Stores.Timeout = %q, %v, want the defaults", c.Port, c.Stores.Timeout)
+				}
+				if got := c.Gothic.Providers["google"].Callback; got != "http://localhost:5173/api/users/auth/google/callback" {
+					t.Errorf("google callback = %q, want the default", got)
+				}
+			},
+		},
+		{
+			name: "file over defaults",
+			file: "port: \"8080\"\nstores:\n  timeout: 2s\n",
+			check: func(t *testing.T, c Config) {
+				if c.Port != "8080" || c.Stores.Timeout != 2*time.Second {
+					t.Errorf("Port, Stores.Timeout = %q, %v, want the file's", c.Port, c.Stores.Timeout)
+				}
+				if c.Host != "0.0.0.0" {
+					t.Errorf("Host = %q, want the default", c.Host)
+				}
+			},
+		},
+		{
+			name: "environment over file",
+			file: "port: \"8080\"\nstores:\n  timeout: 2s\n",
+			env:  map[string]string{"PORT": "9090"},
+			check: func(t *testing.T, c Config) {
+				if c.Port != "9090" || c.Stores.Timeout != 2*time.Second {
+					t.Errorf("Port, Stores.Timeout = %q, %v, want the environment's and the file's", c.Port, c.Stores.Timeout)
+				}
+			},
+		},
+		{
+			name: "environment values",
+			env: map[string]string{
+				"CORS_ALLOW_ORIGINS": " http://a.test, ,http://b.test",
+				"RATE_LIMIT_SEARCH":  "5/10s",
+				"COOKIE_SECURE":      "false",
+				"RATINGS_MIN_VOTES":  "3",
+				"GOOGLE_CALLBACK":    "https://flickmeter.test/callback",
+			},
+			check: func(t *testing.T, c Config) {
+				if want := []string{"http://a.test", "http://b.test"}; !slices.Equal(c.CORS.AllowOrigins, want) {
+					t.Errorf("CORS.AllowOrigins = %v, want %v", c.CORS.AllowOrigins, want)
+				}
+				if want := (RateLimit{Limit: 5, Window: 10 * time.Second}); c.RateLimits.Search != want {
+					t.Errorf("RateLimits.Search = %v, want %v", c.RateLimits.Search, want)
+				}
+				if c.Cookie.Secure || c.Ratings.MinVotes != 3 {
+					t.Errorf("Cookie.Secure, Ratings.MinVotes = %v, %d, want false, 3", c.Cookie.Secure, c.Ratings.MinVotes)
+				}
+				if got := c.Gothic.Providers["google"].Callback; got != "https://flickmeter.test/callback" {
+					t.Errorf("google callback = %q, want the environment's", got)
+				}
+			},
+		},
+		{
+			name:    "missing",
+			env:     map[string]string{"MOVIE_DB_TOKEN": "", "GOOGLE_SECRET": ""},
+			wantErr: []string{"MOVIE_DB_TOKEN: required", "GOOGLE_SECRET: required"},
+		},
+		{
+			name: "unparsable",
+			env: map[string]string{
+				"STORE_TIMEOUT":     "soon",
+				"COOKIE_SECURE":     "maybe",
+				"RATE_LIMIT_SEARCH": "30",
+				"TASTE_MIN_OVERLAP": "some",
+			},
+			wantErr: []string{
+				`STORE_TIMEOUT: invalid duration "soon"`,
+				`COOKIE_SECURE: invalid boolean "maybe"`,
+				`RATE_LIMIT_SEARCH: invalid rate limit "30"`,
+				`TASTE_MIN_OVERLAP: invalid integer "some"`,
+			},
+		},
+		{
+			name:    "invalid file",
+			file:    "port: [\n",
+			wantErr: []string{"failed to parse"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Chdir(dir)
+
+			if tt.file != "" {
+				path := filepath.Join(dir, "config.yaml")
+				if err := os.WriteFile(path, []byte(tt.file), 0o600); err != nil {
+					t.Fatal(err)
+				}
+				t.Setenv("CONFIG_FILE", path)
+			}
+			for key, value := range requiredEnv {
+				t.Setenv(key, value)
+			}
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			c, err := Load()
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("Load() error = %v", err)
+				}
+				tt.check(t, c)
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("Load() error = nil, want %q", tt.wantErr)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Load() error = %v, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadMissingExplicitFile(t *testing.T) {
+	t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "missing.yaml"))
+
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "failed to read") {
+		t.Errorf("Load() error = %v, want a failure to read the file", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := func() Config {
+		c := Default()
+		c.Redis.Address = "localhost:6379"
+		c.Postgres.Address = "postgres://localhost/flickmeter"
+		c.Gothic.CookieStoreKey = "key"
+		c.MovieAPI.Token = "token"
+		c.Gothic.Providers["google"] = oAuthProvider{Client: "client", Secret: "secret"}
+		return c
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(c *Config)
+		wantErr string
+	}{
+		{name: "valid", mutate: func(c *Config) {}},
+		{name: "port", mutate: func(c *Config) { c.Port = "http" }, wantErr: `PORT: invalid port "http"`},
+		{name: "origin", mutate: func(c *Config) { c.CORS.AllowOrigins = []string{"web"} }, wantErr: `CORS_ALLOW_ORIGINS: invalid origin "web"`},
+		{name: "any origin", mutate: func(c *Config) { c.CORS.AllowOrigins = []string{"*"} }},
+		{name: "trusted proxy", mutate: func(c *Config) { c.TrustedProxies = []string{"10.0.0.1"} }, wantErr: `TRUSTED_PROXIES: invalid range "10.0.0.1"`},
+		{name: "same site", mutate: func(c *Config) { c.Cookie.SameSite = "loose" }, wantErr: "COOKIE_SAME_SITE: invalid same site mode"},
+		{
+			name:    "same site none without secure",
+			mutate:  func(c *Config) { c.Cookie.SameSite, c.Cookie.Secure = "none", false },
+			wantErr: "COOKIE_SAME_SITE: none requires COOKIE_SECURE",
+		},
+		{
+			name:    "host prefix with a domain",
+			mutate:  func(c *Config) { c.Cookie.Prefix, c.Cookie.Domain = "__Host-", "flickmeter.test" },
+			wantErr: "COOKIE_PREFIX: __Host- requires",
+		},
+		{name: "rate limit", mutate: func(c *Config) { c.RateLimits.Auth.Limit = 0 }, wantErr: "RATE_LIMIT_AUTH: limit and window must be positive"},
+		{name: "similar ttl", mutate: func(c *Config) { c.Taste.SimilarTTL = 0 }, wantErr: "TASTE_SIMILAR_TTL: must be positive"},
+		{name: "taste overlap", mutate: func(c *Config) { c.Taste.MinOverlap = 1 }, wantErr: "TASTE_MIN_OVERLAP: must be at least 2"},
+		{name: "global mean", mutate: func(c *Config) { c.Ratings.GlobalMean = 11 }, wantErr: "RATINGS_GLOBAL_MEAN: must be between 0 and 10"},
+		{name: "no providers", mutate: func(c *Config) { clear(c.Gothic.Providers) }, wantErr: "PROVIDERS: required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid()
+			tt.mutate(&c)
+
+			errs := c.validate()
+			if tt.wantErr == "" {
+				if len(errs) != 0 {
+					t.Fatalf("validate() = %v, want no errors", errs)
+				}
+				return
+			}
+
+			if len(errs) != 1 || !strings.Contains(errs[0].Error(), tt.wantErr) {
+				t.Errorf("validate() = %v, want one error containing %q", errs, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package cookies
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/config"
+)
+
+// Policy holds the attributes shared by every cookie the server sets, so
+// creating and expiring a cookie always agree on them.
+type Policy struct {
+	Domain   string
+	Path     string
+	Secure   bool
+	SameSite http.SameSite
+	Prefix   string
+}
+
+func NewPolicy(c config.Cookie) Policy {
+	return Policy{
+		Domain:   c.Domain,
+		Path:     c.Path,
+		Secure:   c.Secure,
+		SameSite: c.SameSiteMode(),
+		Prefix:   c.Prefix,
+	}
+}
+
+// Name returns the prefixed name a cookie is stored under.
+func (p Policy) Name(name string) string {
+	return p.Prefix + name
+}
+
+// New returns an http only cookie. A zero expires makes it a session cookie.
+func (p Policy) New(name, value string, expires time.Time) *http.Cookie {
+	return &http.Cookie{
+		Name:     p.Name(name),
+		Value:    value,
+		Domain:   p.Domain,
+		Path:     p.Path,
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   p.Secure,
+		SameSite: p.SameSite,
+	}
+}
+
+// Expire returns a cookie that deletes name from the client.
+func (p Policy) Expire(name string) *http.Cookie {
+	cookie := p.New(name, "", time.Time{})
+	cookie.MaxAge = -1
+	return cookie
+}
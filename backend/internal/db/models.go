@@ -10,6 +10,23 @@ import (
 	"github.com/google/uuid"
 )
 
+type Catalog struct {
+	ID          int32
+	Title       string
+	ReleaseDate string
+	PosterPath  string
+	Genres      []byte
+	Runtime     int32
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type Follow struct {
+	FollowerID int32
+	FolloweeID int32
+	CreatedAt  time.Time
+}
+
 type Movie struct {
 	ID          int32
 	TotalRating int32
@@ -18,6 +35,23 @@ type Movie struct {
 	UpdatedAt   time.Time
 }
 
+type Notification struct {
+	ID        int32
+	UserID    int32
+	ActorID   int32
+	Type      string
+	Data      []byte
+	Read      bool
+	CreatedAt time.Time
+}
+
+type Recommendation struct {
+	UserID    int32
+	MovieID   int32
+	Score     float64
+	CreatedAt time.Time
+}
+
 type Refresh struct {
 	ID        uuid.UUID
 	UserID    int32
@@ -34,6 +68,8 @@ type Review struct {
 	Review    string
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	Language  string
+	Search    interface{}
 }
 
 type User struct {
@@ -52,3 +88,26 @@ type Watchlist struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
+
+type Webhook struct {
+	ID        int32
+	Url       string
+	Secret    string
+	Events    []string
+	Active    bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type WebhookOutbox struct {
+	ID            int32
+	WebhookID     int32
+	EventType     string
+	Payload       []byte
+	Status        string
+	Attempts      int32
+	NextAttemptAt time.Time
+	LastError     string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
@@ -7,10 +7,161 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+const claimWebhookOutbox = `-- name: ClaimWebhookOutbox :many
+UPDATE webhook_outbox
+SET next_attempt_at = $1
+WHERE id IN (
+    SELECT id FROM webhook_outbox
+    WHERE status = 'pending' AND next_attempt_at <= NOW()
+    ORDER BY next_attempt_at
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, webhook_id, event_type, payload, status, attempts, next_attempt_at, last_error, created_at, updated_at
+`
+
+type ClaimWebhookOutboxParams struct {
+	LeaseUntil time.Time
+	Limit      int32
+}
+
+func (q *Queries) ClaimWebhookOutbox(ctx context.Context, arg ClaimWebhookOutboxParams) ([]WebhookOutbox, error) {
+	rows, err := q.db.Query(ctx, claimWebhookOutbox, arg.LeaseUntil, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookOutbox
+	for rows.Next() {
+		var i WebhookOutbox
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.EventType,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastError,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countFollows = `-- name: CountFollows :one
+SELECT
+    (SELECT count(*) FROM follows WHERE followee_id = $1) AS followers,
+    (SELECT count(*) FROM follows WHERE follower_id = $1) AS following
+`
+
+type CountFollowsRow struct {
+	Followers int64
+	Following int64
+}
+
+func (q *Queries) CountFollows(ctx context.Context, userID int32) (CountFollowsRow, error) {
+	row := q.db.QueryRow(ctx, countFollows, userID)
+	var i CountFollowsRow
+	err := row.Scan(
+		&i.Followers,
+		&i.Following,
+	)
+	return i, err
+}
+
+const countUnreadNotifications = `-- name: CountUnreadNotifications :one
+SELECT count(*) FROM notifications
+WHERE user_id = $1 AND NOT read
+`
+
+func (q *Queries) CountUnreadNotifications(ctx context.Context, userID int32) (int64, error) {
+	row := q.db.QueryRow(ctx, countUnreadNotifications, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createFollow = `-- name: CreateFollow :execrows
+INSERT INTO follows (follower_id, followee_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type CreateFollowParams struct {
+	FollowerID int32
+	FolloweeID int32
+}
+
+func (q *Queries) CreateFollow(ctx context.Context, arg CreateFollowParams) (int64, error) {
+	result, err := q.db.Exec(ctx, createFollow, arg.FollowerID, arg.FolloweeID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const createNotification = `-- name: CreateNotification :one
+INSERT INTO notifications (user_id, actor_id, type, data)
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, actor_id, type, data, read, created_at
+`
+
+type CreateNotificationParams struct {
+	UserID  int32
+	ActorID int32
+	Type    string
+	Data    []byte
+}
+
+func (q *Queries) CreateNotification(ctx context.Context, arg CreateNotificationParams) (Notification, error) {
+	row := q.db.QueryRow(ctx, createNotification,
+		arg.UserID,
+		arg.ActorID,
+		arg.Type,
+		arg.Data,
+	)
+	var i Notification
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ActorID,
+		&i.Type,
+		&i.Data,
+		&i.Read,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createRecommendations = `-- name: CreateRecommendations :exec
+INSERT INTO recommendations (user_id, movie_id, score)
+SELECT unnest($1::int[]), unnest($2::int[]), unnest($3::float8[])
+`
+
+type CreateRecommendationsParams struct {
+	UserIds  []int32
+	MovieIds []int32
+	Scores   []float64
+}
+
+func (q *Queries) CreateRecommendations(ctx context.Context, arg CreateRecommendationsParams) error {
+	_, err := q.db.Exec(ctx, createRecommendations, arg.UserIds, arg.MovieIds, arg.Scores)
+	return err
+}
+
 const createRefresh = `-- name: CreateRefresh :exec
 INSERT INTO refresh (id, user_id)
 VALUES ($1, $2)
@@ -26,28 +177,44 @@ func (q *Queries) CreateRefresh(ctx context.Context, arg CreateRefreshParams) er
 	return err
 }
 
-const createReview = `-- name: CreateReview :exec
-INSERT INTO reviews (movie_id, user_id, rating, title, review)
-VALUES ($1, $2, $3, $4, $5)
+const createReview = `-- name: CreateReview :one
+INSERT INTO reviews (movie_id, user_id, rating, title, review, language)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, movie_id, user_id, title, rating, review, created_at, updated_at, language, search
 `
 
 type CreateReviewParams struct {
-	MovieID int32
-	UserID  int32
-	Rating  int32
-	Title   string
-	Review  string
+	MovieID  int32
+	UserID   int32
+	Rating   int32
+	Title    string
+	Review   string
+	Language string
 }
 
-func (q *Queries) CreateReview(ctx context.Context, arg CreateReviewParams) error {
-	_, err := q.db.Exec(ctx, createReview,
+func (q *Queries) CreateReview(ctx context.Context, arg CreateReviewParams) (Review, error) {
+	row := q.db.QueryRow(ctx, createReview,
 		arg.MovieID,
 		arg.UserID,
 		arg.Rating,
 		arg.Title,
 		arg.Review,
+		arg.Language,
 	)
-	return err
+	var i Review
+	err := row.Scan(
+		&i.ID,
+		&i.MovieID,
+		&i.UserID,
+		&i.Title,
+		&i.Rating,
+		&i.Review,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Language,
+		&i.Search,
+	)
+	return i, err
 }
 
 const createUser = `-- name: CreateUser :one
@@ -76,6 +243,69 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 	return i, err
 }
 
+const createWatchlistEntry = `-- name: CreateWatchlistEntry :execrows
+INSERT INTO watchlists (user_id, movie_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type CreateWatchlistEntryParams struct {
+	UserID  int32
+	MovieID int32
+}
+
+func (q *Queries) CreateWatchlistEntry(ctx context.Context, arg CreateWatchlistEntryParams) (int64, error) {
+	result, err := q.db.Exec(ctx, createWatchlistEntry, arg.UserID, arg.MovieID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const createWebhook = `-- name: CreateWebhook :one
+INSERT INTO webhooks (url, secret, events)
+VALUES ($1, $2, $3)
+RETURNING id, url, secret, events, active, created_at, updated_at
+`
+
+type CreateWebhookParams struct {
+	Url    string
+	Secret string
+	Events []string
+}
+
+func (q *Queries) CreateWebhook(ctx context.Context, arg CreateWebhookParams) (Webhook, error) {
+	row := q.db.QueryRow(ctx, createWebhook, arg.Url, arg.Secret, arg.Events)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Secret,
+		&i.Events,
+		&i.Active,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createWebhookOutbox = `-- name: CreateWebhookOutbox :exec
+INSERT INTO webhook_outbox (webhook_id, event_type, payload)
+SELECT id, $1::text, $2::jsonb
+FROM webhooks
+WHERE active AND $1::text = ANY(events)
+`
+
+type CreateWebhookOutboxParams struct {
+	EventType string
+	Payload   []byte
+}
+
+func (q *Queries) CreateWebhookOutbox(ctx context.Context, arg CreateWebhookOutboxParams) error {
+	_, err := q.db.Exec(ctx, createWebhookOutbox, arg.EventType, arg.Payload)
+	return err
+}
+
 const decrementMovieRating = `-- name: DecrementMovieRating :exec
 UPDATE movies
     SET total_rating = movies.total_rating - $2,
@@ -93,6 +323,43 @@ func (q *Queries) DecrementMovieRating(ctx context.Context, arg DecrementMovieRa
 	return err
 }
 
+const deleteDeliveredWebhookOutbox = `-- name: DeleteDeliveredWebhookOutbox :execrows
+DELETE FROM webhook_outbox
+WHERE status = 'delivered' AND updated_at < $1
+`
+
+func (q *Queries) DeleteDeliveredWebhookOutbox(ctx context.Context, updatedAt time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteDeliveredWebhookOutbox, updatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteFollow = `-- name: DeleteFollow :exec
+DELETE FROM follows
+WHERE follower_id = $1 AND followee_id = $2
+`
+
+type DeleteFollowParams struct {
+	FollowerID int32
+	FolloweeID int32
+}
+
+func (q *Queries) DeleteFollow(ctx context.Context, arg DeleteFollowParams) error {
+	_, err := q.db.Exec(ctx, deleteFollow, arg.FollowerID, arg.FolloweeID)
+	return err
+}
+
+const deleteRecommendations = `-- name: DeleteRecommendations :exec
+DELETE FROM recommendations
+`
+
+func (q *Queries) DeleteRecommendations(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deleteRecommendations)
+	return err
+}
+
 const deleteRefresh = `-- name: DeleteRefresh :exec
 DELETE FROM refresh
 WHERE id = $1
@@ -103,6 +370,19 @@ func (q *Queries) DeleteRefresh(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+const deleteRefreshCreatedBefore = `-- name: DeleteRefreshCreatedBefore :execrows
+DELETE FROM refresh
+WHERE created_at < $1
+`
+
+func (q *Queries) DeleteRefreshCreatedBefore(ctx context.Context, createdAt time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteRefreshCreatedBefore, createdAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const deleteReview = `-- name: DeleteReview :exec
 DELETE FROM reviews
 WHERE id = $1
@@ -113,6 +393,45 @@ func (q *Queries) DeleteReview(ctx context.Context, id int32) error {
 	return err
 }
 
+const deleteWebhook = `-- name: DeleteWebhook :execrows
+DELETE FROM webhooks
+WHERE id = $1
+`
+
+func (q *Queries) DeleteWebhook(ctx context.Context, id int32) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteWebhook, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const failWebhookOutbox = `-- name: FailWebhookOutbox :exec
+UPDATE webhook_outbox
+SET attempts = attempts + 1,
+    last_error = $1,
+    next_attempt_at = $2,
+    status = CASE WHEN attempts + 1 >= $3::int THEN 'dead' ELSE 'pending' END
+WHERE id = $4
+`
+
+type FailWebhookOutboxParams struct {
+	LastError     string
+	NextAttemptAt time.Time
+	MaxAttempts   int32
+	ID            int32
+}
+
+func (q *Queries) FailWebhookOutbox(ctx context.Context, arg FailWebhookOutboxParams) error {
+	_, err := q.db.Exec(ctx, failWebhookOutbox,
+		arg.LastError,
+		arg.NextAttemptAt,
+		arg.MaxAttempts,
+		arg.ID,
+	)
+	return err
+}
+
 const incrementMovieRating = `-- name: IncrementMovieRating :exec
 INSERT INTO movies (id, total_rating, review_count)
 VALUES ($1, $2, 1)
@@ -131,24 +450,790 @@ func (q *Queries) IncrementMovieRating(ctx context.Context, arg IncrementMovieRa
 	return err
 }
 
-const readMovie = `-- name: ReadMovie :one
-SELECT id, total_rating, review_count, created_at, updated_at FROM movies
+const listWebhookOutbox = `-- name: ListWebhookOutbox :many
+SELECT id, webhook_id, event_type, payload, status, attempts, next_attempt_at, last_error, created_at, updated_at FROM webhook_outbox
+WHERE webhook_id = $1 AND status = $2
+ORDER BY id DESC
+LIMIT $3
+`
+
+type ListWebhookOutboxParams struct {
+	WebhookID int32
+	Status    string
+	Limit     int32
+}
+
+func (q *Queries) ListWebhookOutbox(ctx context.Context, arg ListWebhookOutboxParams) ([]WebhookOutbox, error) {
+	rows, err := q.db.Query(ctx, listWebhookOutbox, arg.WebhookID, arg.Status, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookOutbox
+	for rows.Next() {
+		var i WebhookOutbox
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.EventType,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastError,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebhooks = `-- name: ListWebhooks :many
+SELECT id, url, secret, events, active, created_at, updated_at FROM webhooks
+ORDER BY id
+`
+
+func (q *Queries) ListWebhooks(ctx context.Context) ([]Webhook, error) {
+	rows, err := q.db.Query(ctx, listWebhooks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Webhook
+	for rows.Next() {
+		var i Webhook
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Secret,
+			&i.Events,
+			&i.Active,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markAllNotificationsRead = `-- name: MarkAllNotificationsRead :execrows
+UPDATE notifications
+SET read = TRUE
+WHERE user_id = $1 AND NOT read
+`
+
+func (q *Queries) MarkAllNotificationsRead(ctx context.Context, userID int32) (int64, error) {
+	result, err := q.db.Exec(ctx, markAllNotificationsRead, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const markNotificationsRead = `-- name: MarkNotificationsRead :execrows
+UPDATE notifications
+SET read = TRUE
+WHERE user_id = $1 AND id = ANY($2::int[]) AND NOT read
+`
+
+type MarkNotificationsReadParams struct {
+	UserID int32
+	Ids    []int32
+}
+
+func (q *Queries) MarkNotificationsRead(ctx context.Context, arg MarkNotificationsReadParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markNotificationsRead, arg.UserID, arg.Ids)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const markWebhookOutboxDelivered = `-- name: MarkWebhookOutboxDelivered :exec
+UPDATE webhook_outbox
+SET status = 'delivered', attempts = attempts + 1, last_error = ''
 WHERE id = $1
 `
 
-func (q *Queries) ReadMovie(ctx context.Context, id int32) (Movie, error) {
-	row := q.db.QueryRow(ctx, readMovie, id)
-	var i Movie
+func (q *Queries) MarkWebhookOutboxDelivered(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, markWebhookOutboxDelivered, id)
+	return err
+}
+
+const readCatalogMovie = `-- name: ReadCatalogMovie :one
+SELECT id, title, release_date, poster_path, genres, runtime, created_at, updated_at FROM catalog
+WHERE id = $1
+`
+
+func (q *Queries) ReadCatalogMovie(ctx context.Context, id int32) (Catalog, error) {
+	row := q.db.QueryRow(ctx, readCatalogMovie, id)
+	var i Catalog
 	err := row.Scan(
 		&i.ID,
-		&i.TotalRating,
-		&i.ReviewCount,
+		&i.Title,
+		&i.ReleaseDate,
+		&i.PosterPath,
+		&i.Genres,
+		&i.Runtime,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return i, err
 }
 
+const readCatalogMovies = `-- name: ReadCatalogMovies :many
+SELECT id, title, release_date, poster_path, genres, runtime, created_at, updated_at FROM catalog
+WHERE id = ANY($1::int[])
+`
+
+func (q *Queries) ReadCatalogMovies(ctx context.Context, ids []int32) ([]Catalog, error) {
+	rows, err := q.db.Query(ctx, readCatalogMovies, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Catalog
+	for rows.Next() {
+		var i Catalog
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.ReleaseDate,
+			&i.PosterPath,
+			&i.Genres,
+			&i.Runtime,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readCompatibility = `-- name: ReadCompatibility :one
+SELECT count(*) AS overlap,
+    COALESCE(corr(mine.rating, theirs.rating), 0)::float8 AS correlation,
+    corr(mine.rating, theirs.rating) IS NOT NULL AS correlated
+FROM reviews mine
+JOIN reviews theirs ON theirs.movie_id = mine.movie_id
+WHERE mine.user_id = $1 AND theirs.user_id = $2
+`
+
+type ReadCompatibilityParams struct {
+	UserID  int32
+	OtherID int32
+}
+
+type ReadCompatibilityRow struct {
+	Overlap     int64
+	Correlation float64
+	Correlated  bool
+}
+
+func (q *Queries) ReadCompatibility(ctx context.Context, arg ReadCompatibilityParams) (ReadCompatibilityRow, error) {
+	row := q.db.QueryRow(ctx, readCompatibility, arg.UserID, arg.OtherID)
+	var i ReadCompatibilityRow
+	err := row.Scan(
+		&i.Overlap,
+		&i.Correlation,
+		&i.Correlated,
+	)
+	return i, err
+}
+
+const readFavoriteMovieIds = `-- name: ReadFavoriteMovieIds :many
+SELECT movie_id FROM reviews
+WHERE user_id = $1 AND rating >= $2
+ORDER BY rating DESC, created_at DESC
+LIMIT $3
+`
+
+type ReadFavoriteMovieIdsParams struct {
+	UserID int32
+	Rating int32
+	Limit  int32
+}
+
+func (q *Queries) ReadFavoriteMovieIds(ctx context.Context, arg ReadFavoriteMovieIdsParams) ([]int32, error) {
+	rows, err := q.db.Query(ctx, readFavoriteMovieIds, arg.UserID, arg.Rating, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var movie_id int32
+		if err := rows.Scan(&movie_id); err != nil {
+			return nil, err
+		}
+		items = append(items, movie_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readFolloweeReviews = `-- name: ReadFolloweeReviews :many
+SELECT reviews.id, reviews.movie_id, reviews.user_id, reviews.title, reviews.rating, reviews.review, reviews.created_at, reviews.updated_at, reviews.language, reviews.search, users.id, users.username, users.email, users.avatar_url, users.created_at, users.updated_at
+FROM reviews
+JOIN follows ON follows.followee_id = reviews.user_id
+JOIN users ON users.id = reviews.user_id
+WHERE follows.follower_id = $1
+    -- 2 is feed.KindReview
+    AND (reviews.created_at, 2, reviews.user_id, reviews.id)
+        < ($2::timestamptz, $3::int, $4::int, $5::int)
+ORDER BY reviews.created_at DESC, reviews.user_id DESC, reviews.id DESC
+LIMIT $6
+`
+
+type ReadFolloweeReviewsParams struct {
+	FollowerID int32
+	CreatedAt  time.Time
+	Kind       int32
+	UserID     int32
+	ID         int32
+	Limit      int32
+}
+
+type ReadFolloweeReviewsRow struct {
+	Review Review
+	User   User
+}
+
+func (q *Queries) ReadFolloweeReviews(ctx context.Context, arg ReadFolloweeReviewsParams) ([]ReadFolloweeReviewsRow, error) {
+	rows, err := q.db.Query(ctx, readFolloweeReviews,
+		arg.FollowerID,
+		arg.CreatedAt,
+		arg.Kind,
+		arg.UserID,
+		arg.ID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadFolloweeReviewsRow
+	for rows.Next() {
+		var i ReadFolloweeReviewsRow
+		if err := rows.Scan(
+			&i.Review.ID,
+			&i.Review.MovieID,
+			&i.Review.UserID,
+			&i.Review.Title,
+			&i.Review.Rating,
+			&i.Review.Review,
+			&i.Review.CreatedAt,
+			&i.Review.UpdatedAt,
+			&i.Review.Language,
+			&i.Review.Search,
+			&i.User.ID,
+			&i.User.Username,
+			&i.User.Email,
+			&i.User.AvatarUrl,
+			&i.User.CreatedAt,
+			&i.User.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readFolloweeWatchlists = `-- name: ReadFolloweeWatchlists :many
+SELECT watchlists.user_id, watchlists.movie_id, watchlists.watched, watchlists.created_at, watchlists.updated_at, users.id, users.username, users.email, users.avatar_url, users.created_at, users.updated_at
+FROM watchlists
+JOIN follows ON follows.followee_id = watchlists.user_id
+JOIN users ON users.id = watchlists.user_id
+WHERE follows.follower_id = $1
+    -- 1 is feed.KindWatchlist
+    AND (watchlists.created_at, 1, watchlists.user_id, watchlists.movie_id)
+        < ($2::timestamptz, $3::int, $4::int, $5::int)
+ORDER BY watchlists.created_at DESC, watchlists.user_id DESC, watchlists.movie_id DESC
+LIMIT $6
+`
+
+type ReadFolloweeWatchlistsParams struct {
+	FollowerID int32
+	CreatedAt  time.Time
+	Kind       int32
+	UserID     int32
+	ID         int32
+	Limit      int32
+}
+
+type ReadFolloweeWatchlistsRow struct {
+	Watchlist Watchlist
+	User      User
+}
+
+func (q *Queries) ReadFolloweeWatchlists(ctx context.Context, arg ReadFolloweeWatchlistsParams) ([]ReadFolloweeWatchlistsRow, error) {
+	rows, err := q.db.Query(ctx, readFolloweeWatchlists,
+		arg.FollowerID,
+		arg.CreatedAt,
+		arg.Kind,
+		arg.UserID,
+		arg.ID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadFolloweeWatchlistsRow
+	for rows.Next() {
+		var i ReadFolloweeWatchlistsRow
+		if err := rows.Scan(
+			&i.Watchlist.UserID,
+			&i.Watchlist.MovieID,
+			&i.Watchlist.Watched,
+			&i.Watchlist.CreatedAt,
+			&i.Watchlist.UpdatedAt,
+			&i.User.ID,
+			&i.User.Username,
+			&i.User.Email,
+			&i.User.AvatarUrl,
+			&i.User.CreatedAt,
+			&i.User.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readFollowers = `-- name: ReadFollowers :many
+SELECT users.id, users.username, users.email, users.avatar_url, users.created_at, users.updated_at, follows.created_at
+FROM follows
+JOIN users ON users.id = follows.follower_id
+WHERE follows.followee_id = $1
+    AND (follows.created_at, users.id) < ($2::timestamptz, $3::int)
+ORDER BY follows.created_at DESC, users.id DESC
+LIMIT $4
+`
+
+type ReadFollowersParams struct {
+	UserID    int32
+	CreatedAt time.Time
+	ID        int32
+	Limit     int32
+}
+
+type ReadFollowersRow struct {
+	User      User
+	CreatedAt time.Time
+}
+
+func (q *Queries) ReadFollowers(ctx context.Context, arg ReadFollowersParams) ([]ReadFollowersRow, error) {
+	rows, err := q.db.Query(ctx, readFollowers,
+		arg.UserID,
+		arg.CreatedAt,
+		arg.ID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadFollowersRow
+	for rows.Next() {
+		var i ReadFollowersRow
+		if err := rows.Scan(
+			&i.User.ID,
+			&i.User.Username,
+			&i.User.Email,
+			&i.User.AvatarUrl,
+			&i.User.CreatedAt,
+			&i.User.UpdatedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readFollowing = `-- name: ReadFollowing :many
+SELECT users.id, users.username, users.email, users.avatar_url, users.created_at, users.updated_at, follows.created_at
+FROM follows
+JOIN users ON users.id = follows.followee_id
+WHERE follows.follower_id = $1
+    AND (follows.created_at, users.id) < ($2::timestamptz, $3::int)
+ORDER BY follows.created_at DESC, users.id DESC
+LIMIT $4
+`
+
+type ReadFollowingParams struct {
+	UserID    int32
+	CreatedAt time.Time
+	ID        int32
+	Limit     int32
+}
+
+type ReadFollowingRow struct {
+	User      User
+	CreatedAt time.Time
+}
+
+func (q *Queries) ReadFollowing(ctx context.Context, arg ReadFollowingParams) ([]ReadFollowingRow, error) {
+	rows, err := q.db.Query(ctx, readFollowing,
+		arg.UserID,
+		arg.CreatedAt,
+		arg.ID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadFollowingRow
+	for rows.Next() {
+		var i ReadFollowingRow
+		if err := rows.Scan(
+			&i.User.ID,
+			&i.User.Username,
+			&i.User.Email,
+			&i.User.AvatarUrl,
+			&i.User.CreatedAt,
+			&i.User.UpdatedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readMostReviewedMovies = `-- name: ReadMostReviewedMovies :many
+SELECT movie_id, count(*) AS review_count, avg(rating)::float8 AS average_rating
+FROM reviews
+WHERE created_at >= $1
+GROUP BY movie_id
+ORDER BY review_count DESC, average_rating DESC, movie_id
+LIMIT $2
+`
+
+type ReadMostReviewedMoviesParams struct {
+	Since time.Time
+	Limit int32
+}
+
+type ReadMostReviewedMoviesRow struct {
+	MovieID       int32
+	ReviewCount   int64
+	AverageRating float64
+}
+
+func (q *Queries) ReadMostReviewedMovies(ctx context.Context, arg ReadMostReviewedMoviesParams) ([]ReadMostReviewedMoviesRow, error) {
+	rows, err := q.db.Query(ctx, readMostReviewedMovies, arg.Since, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadMostReviewedMoviesRow
+	for rows.Next() {
+		var i ReadMostReviewedMoviesRow
+		if err := rows.Scan(
+			&i.MovieID,
+			&i.ReviewCount,
+			&i.AverageRating,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readMovie = `-- name: ReadMovie :one
+SELECT id, total_rating, review_count, created_at, updated_at FROM movies
+WHERE id = $1
+`
+
+func (q *Queries) ReadMovie(ctx context.Context, id int32) (Movie, error) {
+	row := q.db.QueryRow(ctx, readMovie, id)
+	var i Movie
+	err := row.Scan(
+		&i.ID,
+		&i.TotalRating,
+		&i.ReviewCount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const readMovies = `-- name: ReadMovies :many
+SELECT id, total_rating, review_count, created_at, updated_at FROM movies
+WHERE id = ANY($1::int[])
+`
+
+func (q *Queries) ReadMovies(ctx context.Context, ids []int32) ([]Movie, error) {
+	rows, err := q.db.Query(ctx, readMovies, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Movie
+	for rows.Next() {
+		var i Movie
+		if err := rows.Scan(
+			&i.ID,
+			&i.TotalRating,
+			&i.ReviewCount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readNotifications = `-- name: ReadNotifications :many
+SELECT notifications.id, notifications.user_id, notifications.actor_id, notifications.type, notifications.data, notifications.read, notifications.created_at, users.id, users.username, users.email, users.avatar_url, users.created_at, users.updated_at
+FROM notifications
+JOIN users ON users.id = notifications.actor_id
+WHERE notifications.user_id = $1
+    AND (NOT $2::bool OR NOT notifications.read)
+    AND (notifications.created_at, notifications.id) < ($3::timestamptz, $4::int)
+ORDER BY notifications.created_at DESC, notifications.id DESC
+LIMIT $5
+`
+
+type ReadNotificationsParams struct {
+	UserID     int32
+	UnreadOnly bool
+	CreatedAt  time.Time
+	ID         int32
+	Limit      int32
+}
+
+type ReadNotificationsRow struct {
+	Notification Notification
+	User         User
+}
+
+func (q *Queries) ReadNotifications(ctx context.Context, arg ReadNotificationsParams) ([]ReadNotificationsRow, error) {
+	rows, err := q.db.Query(ctx, readNotifications,
+		arg.UserID,
+		arg.UnreadOnly,
+		arg.CreatedAt,
+		arg.ID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadNotificationsRow
+	for rows.Next() {
+		var i ReadNotificationsRow
+		if err := rows.Scan(
+			&i.Notification.ID,
+			&i.Notification.UserID,
+			&i.Notification.ActorID,
+			&i.Notification.Type,
+			&i.Notification.Data,
+			&i.Notification.Read,
+			&i.Notification.CreatedAt,
+			&i.User.ID,
+			&i.User.Username,
+			&i.User.Email,
+			&i.User.AvatarUrl,
+			&i.User.CreatedAt,
+			&i.User.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readRatings = `-- name: ReadRatings :many
+SELECT user_id, movie_id, rating FROM reviews
+`
+
+type ReadRatingsRow struct {
+	UserID  int32
+	MovieID int32
+	Rating  int32
+}
+
+func (q *Queries) ReadRatings(ctx context.Context) ([]ReadRatingsRow, error) {
+	rows, err := q.db.Query(ctx, readRatings)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadRatingsRow
+	for rows.Next() {
+		var i ReadRatingsRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.MovieID,
+			&i.Rating,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readRecentReviews = `-- name: ReadRecentReviews :many
+SELECT reviews.id, reviews.movie_id, reviews.user_id, reviews.title, reviews.rating, reviews.review, reviews.created_at, reviews.updated_at, reviews.language, reviews.search, users.id, users.username, users.email, users.avatar_url, users.created_at, users.updated_at
+FROM reviews
+JOIN users ON reviews.user_id = users.id
+WHERE (reviews.created_at, reviews.id) < ($1::timestamptz, $2::int)
+ORDER BY reviews.created_at DESC, reviews.id DESC
+LIMIT $3
+`
+
+type ReadRecentReviewsParams struct {
+	CreatedAt time.Time
+	ID        int32
+	Limit     int32
+}
+
+type ReadRecentReviewsRow struct {
+	Review Review
+	User   User
+}
+
+func (q *Queries) ReadRecentReviews(ctx context.Context, arg ReadRecentReviewsParams) ([]ReadRecentReviewsRow, error) {
+	rows, err := q.db.Query(ctx, readRecentReviews, arg.CreatedAt, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadRecentReviewsRow
+	for rows.Next() {
+		var i ReadRecentReviewsRow
+		if err := rows.Scan(
+			&i.Review.ID,
+			&i.Review.MovieID,
+			&i.Review.UserID,
+			&i.Review.Title,
+			&i.Review.Rating,
+			&i.Review.Review,
+			&i.Review.CreatedAt,
+			&i.Review.UpdatedAt,
+			&i.Review.Language,
+			&i.Review.Search,
+			&i.User.ID,
+			&i.User.Username,
+			&i.User.Email,
+			&i.User.AvatarUrl,
+			&i.User.CreatedAt,
+			&i.User.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readRecommendations = `-- name: ReadRecommendations :many
+SELECT movie_id, score FROM recommendations
+WHERE recommendations.user_id = $1
+    AND NOT EXISTS (
+        SELECT 1 FROM reviews
+        WHERE reviews.user_id = recommendations.user_id AND reviews.movie_id = recommendations.movie_id
+    )
+    AND NOT EXISTS (
+        SELECT 1 FROM watchlists
+        WHERE watchlists.user_id = recommendations.user_id AND watchlists.movie_id = recommendations.movie_id
+            AND watchlists.watched
+    )
+ORDER BY score DESC, movie_id
+LIMIT $2
+`
+
+type ReadRecommendationsParams struct {
+	UserID int32
+	Limit  int32
+}
+
+type ReadRecommendationsRow struct {
+	MovieID int32
+	Score   float64
+}
+
+func (q *Queries) ReadRecommendations(ctx context.Context, arg ReadRecommendationsParams) ([]ReadRecommendationsRow, error) {
+	rows, err := q.db.Query(ctx, readRecommendations, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadRecommendationsRow
+	for rows.Next() {
+		var i ReadRecommendationsRow
+		if err := rows.Scan(
+			&i.MovieID,
+			&i.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const readRefresh = `-- name: ReadRefresh :one
 SELECT r.id, r.user_id, r.created_at, r.updated_at, u.id, u.username, u.email, u.avatar_url, u.created_at, u.updated_at
 FROM refresh r
@@ -180,7 +1265,7 @@ func (q *Queries) ReadRefresh(ctx context.Context, id uuid.UUID) (ReadRefreshRow
 }
 
 const readReview = `-- name: ReadReview :one
-SELECT reviews.id, reviews.movie_id, reviews.user_id, reviews.title, reviews.rating, reviews.review, reviews.created_at, reviews.updated_at, users.id, users.username, users.email, users.avatar_url, users.created_at, users.updated_at
+SELECT reviews.id, reviews.movie_id, reviews.user_id, reviews.title, reviews.rating, reviews.review, reviews.created_at, reviews.updated_at, reviews.language, reviews.search, users.id, users.username, users.email, users.avatar_url, users.created_at, users.updated_at
 FROM reviews
 JOIN users ON reviews.user_id = users.id
 WHERE reviews.id = $1
@@ -203,6 +1288,8 @@ func (q *Queries) ReadReview(ctx context.Context, id int32) (ReadReviewRow, erro
 		&i.Review.Review,
 		&i.Review.CreatedAt,
 		&i.Review.UpdatedAt,
+		&i.Review.Language,
+		&i.Review.Search,
 		&i.User.ID,
 		&i.User.Username,
 		&i.User.Email,
@@ -214,7 +1301,7 @@ func (q *Queries) ReadReview(ctx context.Context, id int32) (ReadReviewRow, erro
 }
 
 const readReviews = `-- name: ReadReviews :many
-SELECT reviews.id, reviews.movie_id, reviews.user_id, reviews.title, reviews.rating, reviews.review, reviews.created_at, reviews.updated_at, users.id, users.username, users.email, users.avatar_url, users.created_at, users.updated_at
+SELECT reviews.id, reviews.movie_id, reviews.user_id, reviews.title, reviews.rating, reviews.review, reviews.created_at, reviews.updated_at, reviews.language, reviews.search, users.id, users.username, users.email, users.avatar_url, users.created_at, users.updated_at
 FROM reviews
 JOIN users ON reviews.user_id = users.id
 WHERE reviews.movie_id = $1
@@ -251,12 +1338,250 @@ func (q *Queries) ReadReviews(ctx context.Context, arg ReadReviewsParams) ([]Rea
 			&i.Review.Review,
 			&i.Review.CreatedAt,
 			&i.Review.UpdatedAt,
+			&i.Review.Language,
+			&i.Review.Search,
+			&i.User.ID,
+			&i.User.Username,
+			&i.User.Email,
+			&i.User.AvatarUrl,
+			&i.User.CreatedAt,
+			&i.User.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readSeenMovieIds = `-- name: ReadSeenMovieIds :many
+SELECT movie_id FROM reviews WHERE reviews.user_id = $1
+UNION
+SELECT movie_id FROM watchlists WHERE watchlists.user_id = $1 AND watched
+`
+
+func (q *Queries) ReadSeenMovieIds(ctx context.Context, userID int32) ([]int32, error) {
+	rows, err := q.db.Query(ctx, readSeenMovieIds, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var movie_id int32
+		if err := rows.Scan(&movie_id); err != nil {
+			return nil, err
+		}
+		items = append(items, movie_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readSimilarUsers = `-- name: ReadSimilarUsers :many
+SELECT users.id, users.username, users.email, users.avatar_url, users.created_at, users.updated_at, similar.overlap, similar.correlation
+FROM (
+    SELECT theirs.user_id, count(*) AS overlap, corr(mine.rating, theirs.rating)::float8 AS correlation
+    FROM reviews mine
+    JOIN reviews theirs ON theirs.movie_id = mine.movie_id AND theirs.user_id <> mine.user_id
+    WHERE mine.user_id = $1
+    GROUP BY theirs.user_id
+    HAVING count(*) >= $2::int AND corr(mine.rating, theirs.rating) IS NOT NULL
+) similar
+JOIN users ON users.id = similar.user_id
+ORDER BY similar.correlation DESC, similar.overlap DESC, users.id
+LIMIT $3
+`
+
+type ReadSimilarUsersParams struct {
+	UserID     int32
+	MinOverlap int32
+	Limit      int32
+}
+
+type ReadSimilarUsersRow struct {
+	User        User
+	Overlap     int64
+	Correlation float64
+}
+
+func (q *Queries) ReadSimilarUsers(ctx context.Context, arg ReadSimilarUsersParams) ([]ReadSimilarUsersRow, error) {
+	rows, err := q.db.Query(ctx, readSimilarUsers, arg.UserID, arg.MinOverlap, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadSimilarUsersRow
+	for rows.Next() {
+		var i ReadSimilarUsersRow
+		if err := rows.Scan(
 			&i.User.ID,
 			&i.User.Username,
 			&i.User.Email,
 			&i.User.AvatarUrl,
 			&i.User.CreatedAt,
 			&i.User.UpdatedAt,
+			&i.Overlap,
+			&i.Correlation,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readStaleCatalogIds = `-- name: ReadStaleCatalogIds :many
+SELECT id FROM catalog
+WHERE updated_at < $1
+ORDER BY updated_at
+LIMIT $2
+`
+
+type ReadStaleCatalogIdsParams struct {
+	UpdatedAt time.Time
+	Limit     int32
+}
+
+func (q *Queries) ReadStaleCatalogIds(ctx context.Context, arg ReadStaleCatalogIdsParams) ([]int32, error) {
+	rows, err := q.db.Query(ctx, readStaleCatalogIds, arg.UpdatedAt, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readTopRatedMovies = `-- name: ReadTopRatedMovies :many
+WITH stats AS (
+    SELECT movie_id, count(*) AS review_count, avg(rating)::float8 AS average_rating
+    FROM reviews
+    WHERE created_at >= $1
+    GROUP BY movie_id
+), prior AS (
+    SELECT COALESCE(NULLIF($2::float8, 0), avg(rating), 0)::float8 AS mean
+    FROM reviews
+    WHERE created_at >= $1
+)
+SELECT stats.movie_id, stats.review_count, stats.average_rating,
+    ((stats.review_count * stats.average_rating + $3::int * prior.mean)
+        / (stats.review_count + $3::int))::float8 AS weighted_rating
+FROM stats, prior
+ORDER BY weighted_rating DESC, stats.review_count DESC, stats.movie_id
+LIMIT $4
+`
+
+type ReadTopRatedMoviesParams struct {
+	Since      time.Time
+	GlobalMean float64
+	MinVotes   int32
+	Limit      int32
+}
+
+type ReadTopRatedMoviesRow struct {
+	MovieID        int32
+	ReviewCount    int64
+	AverageRating  float64
+	WeightedRating float64
+}
+
+func (q *Queries) ReadTopRatedMovies(ctx context.Context, arg ReadTopRatedMoviesParams) ([]ReadTopRatedMoviesRow, error) {
+	rows, err := q.db.Query(ctx, readTopRatedMovies,
+		arg.Since,
+		arg.GlobalMean,
+		arg.MinVotes,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadTopRatedMoviesRow
+	for rows.Next() {
+		var i ReadTopRatedMoviesRow
+		if err := rows.Scan(
+			&i.MovieID,
+			&i.ReviewCount,
+			&i.AverageRating,
+			&i.WeightedRating,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readTrendingScores = `-- name: ReadTrendingScores :many
+SELECT movie_id,
+    sum(weight * exp(-ln(2) * extract(epoch FROM NOW() - created_at) / $1::float8))::float8 AS score
+FROM (
+    SELECT movie_id, created_at, $2::float8 AS weight
+    FROM reviews
+    WHERE created_at >= $3
+    UNION ALL
+    SELECT movie_id, created_at, $4::float8 AS weight
+    FROM watchlists
+    WHERE created_at >= $3
+) activity
+GROUP BY movie_id
+ORDER BY score DESC, movie_id
+LIMIT $5
+`
+
+type ReadTrendingScoresParams struct {
+	HalfLife        float64
+	ReviewWeight    float64
+	Since           time.Time
+	WatchlistWeight float64
+	Limit           int32
+}
+
+type ReadTrendingScoresRow struct {
+	MovieID int32
+	Score   float64
+}
+
+func (q *Queries) ReadTrendingScores(ctx context.Context, arg ReadTrendingScoresParams) ([]ReadTrendingScoresRow, error) {
+	rows, err := q.db.Query(ctx, readTrendingScores,
+		arg.HalfLife,
+		arg.ReviewWeight,
+		arg.Since,
+		arg.WatchlistWeight,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadTrendingScoresRow
+	for rows.Next() {
+		var i ReadTrendingScoresRow
+		if err := rows.Scan(
+			&i.MovieID,
+			&i.Score,
 		); err != nil {
 			return nil, err
 		}
@@ -308,8 +1633,28 @@ func (q *Queries) ReadUserByEmail(ctx context.Context, email string) (User, erro
 	return i, err
 }
 
+const readUserByUsername = `-- name: ReadUserByUsername :one
+SELECT *
+FROM users
+WHERE username = $1
+`
+
+func (q *Queries) ReadUserByUsername(ctx context.Context, username string) (User, error) {
+	row := q.db.QueryRow(ctx, readUserByUsername, username)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.AvatarUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const readUserReview = `-- name: ReadUserReview :one
-SELECT reviews.id, reviews.movie_id, reviews.user_id, reviews.title, reviews.rating, reviews.review, reviews.created_at, reviews.updated_at, users.id, users.username, users.email, users.avatar_url, users.created_at, users.updated_at
+SELECT reviews.id, reviews.movie_id, reviews.user_id, reviews.title, reviews.rating, reviews.review, reviews.created_at, reviews.updated_at, reviews.language, reviews.search, users.id, users.username, users.email, users.avatar_url, users.created_at, users.updated_at
 FROM reviews
 JOIN users ON reviews.user_id = users.id
 WHERE reviews.movie_id = $1 AND reviews.user_id = $2
@@ -337,6 +1682,8 @@ func (q *Queries) ReadUserReview(ctx context.Context, arg ReadUserReviewParams)
 		&i.Review.Review,
 		&i.Review.CreatedAt,
 		&i.Review.UpdatedAt,
+		&i.Review.Language,
+		&i.Review.Search,
 		&i.User.ID,
 		&i.User.Username,
 		&i.User.Email,
@@ -347,6 +1694,193 @@ func (q *Queries) ReadUserReview(ctx context.Context, arg ReadUserReviewParams)
 	return i, err
 }
 
+const readWebhooks = `-- name: ReadWebhooks :many
+SELECT id, url, secret, events, active, created_at, updated_at FROM webhooks
+WHERE id = ANY($1::int[])
+`
+
+func (q *Queries) ReadWebhooks(ctx context.Context, ids []int32) ([]Webhook, error) {
+	rows, err := q.db.Query(ctx, readWebhooks, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Webhook
+	for rows.Next() {
+		var i Webhook
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Secret,
+			&i.Events,
+			&i.Active,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readWeightedRatings = `-- name: ReadWeightedRatings :many
+WITH prior AS (
+    SELECT COALESCE(NULLIF($1::float8, 0), sum(total_rating)::float8 / NULLIF(sum(review_count), 0), 0)::float8 AS mean
+    FROM movies
+)
+SELECT movies.id,
+    ((movies.total_rating + $2::int * prior.mean)
+        / (movies.review_count + $2::int))::float8 AS weighted_rating
+FROM movies, prior
+WHERE movies.id = ANY($3::int[]) AND movies.review_count > 0
+`
+
+type ReadWeightedRatingsParams struct {
+	GlobalMean float64
+	MinVotes   int32
+	Ids        []int32
+}
+
+type ReadWeightedRatingsRow struct {
+	ID             int32
+	WeightedRating float64
+}
+
+func (q *Queries) ReadWeightedRatings(ctx context.Context, arg ReadWeightedRatingsParams) ([]ReadWeightedRatingsRow, error) {
+	rows, err := q.db.Query(ctx, readWeightedRatings, arg.GlobalMean, arg.MinVotes, arg.Ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadWeightedRatingsRow
+	for rows.Next() {
+		var i ReadWeightedRatingsRow
+		if err := rows.Scan(&i.ID, &i.WeightedRating); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const retryWebhookOutbox = `-- name: RetryWebhookOutbox :execrows
+UPDATE webhook_outbox
+SET status = 'pending', attempts = 0, next_attempt_at = NOW()
+WHERE id = $1 AND webhook_id = $2 AND status = 'dead'
+`
+
+type RetryWebhookOutboxParams struct {
+	ID        int32
+	WebhookID int32
+}
+
+func (q *Queries) RetryWebhookOutbox(ctx context.Context, arg RetryWebhookOutboxParams) (int64, error) {
+	result, err := q.db.Exec(ctx, retryWebhookOutbox, arg.ID, arg.WebhookID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const searchReviews = `-- name: SearchReviews :many
+WITH queries AS (
+    SELECT config::regconfig AS language, websearch_to_tsquery(config::regconfig, $2) AS query
+    FROM unnest($1::text[]) AS config
+)
+SELECT reviews.id, reviews.movie_id, reviews.user_id, reviews.title, reviews.rating, reviews.review, reviews.created_at, reviews.updated_at, reviews.language, reviews.search, users.id, users.username, users.email, users.avatar_url, users.created_at, users.updated_at,
+    ts_headline(reviews.language, reviews.title, queries.query,
+        'HighlightAll=true, StartSel=<mark>, StopSel=</mark>')::text AS title_highlight,
+    ts_headline(reviews.language, reviews.review, queries.query,
+        'MaxFragments=2, MaxWords=30, MinWords=10, StartSel=<mark>, StopSel=</mark>')::text AS snippet,
+    ts_rank(reviews.search, queries.query)::float8 AS rank,
+    count(*) OVER () AS total
+FROM queries
+JOIN reviews ON reviews.language = queries.language AND reviews.search @@ queries.query
+JOIN users ON reviews.user_id = users.id
+WHERE ($3::int = 0 OR reviews.movie_id = $3)
+    AND ($4::int = 0 OR reviews.user_id = $4)
+    AND reviews.rating BETWEEN $5::int AND $6::int
+ORDER BY rank DESC, reviews.id DESC
+LIMIT $7 OFFSET $8
+`
+
+type SearchReviewsParams struct {
+	Languages []string
+	Query     string
+	MovieID   int32
+	UserID    int32
+	MinRating int32
+	MaxRating int32
+	Limit     int32
+	Offset    int32
+}
+
+type SearchReviewsRow struct {
+	Review         Review
+	User           User
+	TitleHighlight string
+	Snippet        string
+	Rank           float64
+	Total          int64
+}
+
+func (q *Queries) SearchReviews(ctx context.Context, arg SearchReviewsParams) ([]SearchReviewsRow, error) {
+	rows, err := q.db.Query(ctx, searchReviews,
+		arg.Languages,
+		arg.Query,
+		arg.MovieID,
+		arg.UserID,
+		arg.MinRating,
+		arg.MaxRating,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchReviewsRow
+	for rows.Next() {
+		var i SearchReviewsRow
+		if err := rows.Scan(
+			&i.Review.ID,
+			&i.Review.MovieID,
+			&i.Review.UserID,
+			&i.Review.Title,
+			&i.Review.Rating,
+			&i.Review.Review,
+			&i.Review.CreatedAt,
+			&i.Review.UpdatedAt,
+			&i.Review.Language,
+			&i.Review.Search,
+			&i.User.ID,
+			&i.User.Username,
+			&i.User.Email,
+			&i.User.AvatarUrl,
+			&i.User.CreatedAt,
+			&i.User.UpdatedAt,
+			&i.TitleHighlight,
+			&i.Snippet,
+			&i.Rank,
+			&i.Total,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateMovieRating = `-- name: UpdateMovieRating :exec
 UPDATE movies
 SET total_rating = total_rating - $2 + $3
@@ -366,17 +1900,18 @@ func (q *Queries) UpdateMovieRating(ctx context.Context, arg UpdateMovieRatingPa
 
 const updateReview = `-- name: UpdateReview :one
 UPDATE reviews
-SET title = $2, rating = $3, review = $4
+SET title = $2, rating = $3, review = $4, language = $5
 FROM users
 WHERE reviews.id = $1
-RETURNING reviews.id, reviews.movie_id, reviews.user_id, reviews.title, reviews.rating, reviews.review, reviews.created_at, reviews.updated_at, users.id, users.username, users.email, users.avatar_url, users.created_at, users.updated_at
+RETURNING reviews.id, reviews.movie_id, reviews.user_id, reviews.title, reviews.rating, reviews.review, reviews.created_at, reviews.updated_at, reviews.language, reviews.search, users.id, users.username, users.email, users.avatar_url, users.created_at, users.updated_at
 `
 
 type UpdateReviewParams struct {
-	ID     int32
-	Title  string
-	Rating int32
-	Review string
+	ID       int32
+	Title    string
+	Rating   int32
+	Review   string
+	Language string
 }
 
 type UpdateReviewRow struct {
@@ -390,6 +1925,7 @@ func (q *Queries) UpdateReview(ctx context.Context, arg UpdateReviewParams) (Upd
 		arg.Title,
 		arg.Rating,
 		arg.Review,
+		arg.Language,
 	)
 	var i UpdateReviewRow
 	err := row.Scan(
@@ -401,6 +1937,8 @@ func (q *Queries) UpdateReview(ctx context.Context, arg UpdateReviewParams) (Upd
 		&i.Review.Review,
 		&i.Review.CreatedAt,
 		&i.Review.UpdatedAt,
+		&i.Review.Language,
+		&i.Review.Search,
 		&i.User.ID,
 		&i.User.Username,
 		&i.User.Email,
@@ -410,3 +1948,35 @@ func (q *Queries) UpdateReview(ctx context.Context, arg UpdateReviewParams) (Upd
 	)
 	return i, err
 }
+
+const upsertCatalogMovie = `-- name: UpsertCatalogMovie :exec
+INSERT INTO catalog (id, title, release_date, poster_path, genres, runtime)
+VALUES ($1, $2, $3, $4, $5, $6)
+    ON CONFLICT (id) DO UPDATE
+    SET title = EXCLUDED.title,
+    release_date = EXCLUDED.release_date,
+    poster_path = EXCLUDED.poster_path,
+    genres = EXCLUDED.genres,
+    runtime = EXCLUDED.runtime
+`
+
+type UpsertCatalogMovieParams struct {
+	ID          int32
+	Title       string
+	ReleaseDate string
+	PosterPath  string
+	Genres      []byte
+	Runtime     int32
+}
+
+func (q *Queries) UpsertCatalogMovie(ctx context.Context, arg UpsertCatalogMovieParams) error {
+	_, err := q.db.Exec(ctx, upsertCatalogMovie,
+		arg.ID,
+		arg.Title,
+		arg.ReleaseDate,
+		arg.PosterPath,
+		arg.Genres,
+		arg.Runtime,
+	)
+	return err
+}
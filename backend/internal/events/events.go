@@ -0,0 +1,299 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/config"
+)
+
+const (
+	channelPrefix = "events:"
+	historyPrefix = "events:history:"
+
+	subscriptionBuffer = 16
+
+	resubscribeBase = 100 * time.Millisecond
+	resubscribeMax  = 30 * time.Second
+)
+
+var (
+	ErrTooManyConnections = errors.New("events: too many connections")
+	ErrInvalidId          = errors.New("events: invalid event id")
+)
+
+// publishScript appends the event to the topic's capped history and then
+// publishes it, so every replica sees it with the id it was stored under.
+var publishScript = redis.NewScript(`
+local id = redis.call('XADD', KEYS[1], 'MAXLEN', '~', ARGV[1], '*', 'type', ARGV[2], 'data', ARGV[3])
+redis.call('PEXPIRE', KEYS[1], ARGV[4])
+redis.call('PUBLISH', KEYS[2], cjson.encode({id = id, type = ARGV[2], data = ARGV[3]}))
+return id
+`)
+
+// Event is a message published to a topic. Ids are Redis stream ids, which
+// grow with every event published to the same topic.
+type Event struct {
+	Id   string          `json:"id"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+func MovieTopic(id int32) string {
+	return fmt.Sprintf("movie:%d", id)
+}
+
+func UserTopic(id int32) string {
+	return fmt.Sprintf("user:%d", id)
+}
+
+type (
+	Logger interface {
+		Errorf(format string, args ...any)
+	}
+
+	// Broker publishes events through Redis pub/sub and fans them out to the
+	// subscriptions of this replica.
+	Broker struct {
+		client  redis.Client
+		timeout time.Duration
+		config  config.Events
+		logger  Logger
+
+		mu      sync.Mutex
+		topics  map[string]map[*Subscription]struct{}
+		clients map[string]int
+		total   int
+	}
+
+	Subscription struct {
+		C <-chan Event
+
+		c      chan Event
+		topic  string
+		client string
+		broker *Broker
+		closed bool
+	}
+)
+
+func NewBroker(client redis.Client, timeout time.Duration, c config.Events, logger Logger) *Broker {
+	return &Broker{
+		client:  client,
+		timeout: timeout,
+		config:  c,
+		logger:  logger,
+		topics:  make(map[string]map[*Subscription]struct{}),
+		clients: make(map[string]int),
+	}
+}
+
+// Publish sends an event with data encoded as JSON to every subscriber of
+// topic, on every replica.
+func (b *Broker) Publish(c context.Context, topic, typ string, data any) error {
+	ctx, cancel := context.WithTimeout(c, b.timeout)
+	defer cancel()
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	keys := []string{historyPrefix + topic, channelPrefix + topic}
+	args := []any{b.config.History, typ, payload, b.config.HistoryTTL.Milliseconds()}
+
+	return publishScript.Run(ctx, &b.client, keys, args...).Err()
+}
+
+// Since returns the events published to topic after the one with lastId that
+// are still in its history, oldest first.
+func (b *Broker) Since(c context.Context, topic, lastId string) ([]Event, error) {
+	if _, _, err := parseId(lastId); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(c, b.timeout)
+	defer cancel()
+
+	messages, err := b.client.XRange(ctx, historyPrefix+topic, "("+lastId, "+").Result()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]Event, 0, len(messages))
+	for _, m := range messages {
+		typ, _ := m.Values["type"].(string)
+		data, _ := m.Values["data"].(string)
+		events = append(events, Event{Id: m.ID, Type: typ, Data: json.RawMessage(data)})
+	}
+
+	return events, nil
+}
+
+// Run receives the events published by every replica until ctx is done,
+// subscribing again with backoff whenever the subscription is lost. Events
+// published meanwhile are missed, clients catch up with Last-Event-ID.
+func (b *Broker) Run(ctx context.Context) error {
+	for attempt := 0; ; attempt++ {
+		subscribed, err := b.receive(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if subscribed {
+			attempt = 0
+		}
+
+		wait := min(resubscribeBase<<min(attempt, 10), resubscribeMax)
+		b.logger.Errorf("events: subscribing again in %v: %v", wait, err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// receive dispatches the events published by every replica until the
+// subscription ends, reporting whether it was ever set up.
+func (b *Broker) receive(ctx context.Context) (bool, error) {
+	pubsub := b.client.PSubscribe(ctx, channelPrefix+"*")
+	defer func() { _ = pubsub.Close() }()
+
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return false, err
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return true, ctx.Err()
+		case m, ok := <-messages:
+			if !ok {
+				return true, errors.New("events: subscription closed")
+			}
+
+			var wire struct {
+				Id   string `json:"id"`
+				Type string `json:"type"`
+				Data string `json:"data"`
+			}
+			if err := json.Unmarshal([]byte(m.Payload), &wire); err != nil {
+				continue
+			}
+
+			topic := strings.TrimPrefix(m.Channel, channelPrefix)
+			b.dispatch(topic, Event{Id: wire.Id, Type: wire.Type, Data: json.RawMessage(wire.Data)})
+		}
+	}
+}
+
+// Subscribe starts receiving the events of topic for client, which is
+// limited to MaxConnectionsPerClient subscriptions.
+func (b *Broker) Subscribe(topic, client string) (*Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.total >= b.config.MaxConnections || b.clients[client] >= b.config.MaxConnectionsPerClient {
+		return nil, ErrTooManyConnections
+	}
+
+	c := make(chan Event, subscriptionBuffer)
+	s := &Subscription{C: c, c: c, topic: topic, client: client, broker: b}
+
+	if b.topics[topic] == nil {
+		b.topics[topic] = make(map[*Subscription]struct{})
+	}
+	b.topics[topic][s] = struct{}{}
+	b.clients[client]++
+	b.total++
+
+	return s, nil
+}
+
+// Close ends every subscription, so streams don't hold up a shutdown.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, subs := range b.topics {
+		for s := range subs {
+			b.remove(s)
+		}
+	}
+}
+
+// dispatch hands event to the subscribers of topic. Subscribers too slow to
+// keep up are dropped, they can catch up by reconnecting with Last-Event-ID.
+func (b *Broker) dispatch(topic string, event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for s := range b.topics[topic] {
+		select {
+		case s.c <- event:
+		default:
+			b.remove(s)
+		}
+	}
+}
+
+// Close ends the subscription, closing C.
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	s.broker.remove(s)
+}
+
+func (b *Broker) remove(s *Subscription) {
+	if s.closed {
+		return
+	}
+	s.closed = true
+	close(s.c)
+
+	delete(b.topics[s.topic], s)
+	if len(b.topics[s.topic]) == 0 {
+		delete(b.topics, s.topic)
+	}
+
+	if b.clients[s.client]--; b.clients[s.client] == 0 {
+		delete(b.clients, s.client)
+	}
+	b.total--
+}
+
+// After reports whether id comes after other, both being event ids.
+func After(id, other string) bool {
+	ms, seq, err := parseId(id)
+	otherMs, otherSeq, otherErr := parseId(other)
+	if err != nil || otherErr != nil {
+		return true
+	}
+
+	return ms > otherMs || ms == otherMs && seq > otherSeq
+}
+
+func parseId(id string) (ms, seq uint64, err error) {
+	msPart, seqPart, found := strings.Cut(id, "-")
+	if !found {
+		return 0, 0, ErrInvalidId
+	}
+
+	ms, err1 := strconv.ParseUint(msPart, 10, 64)
+	seq, err2 := strconv.ParseUint(seqPart, 10, 64)
+	if err1 != nil || err2 != nil {
+		return 0, 0, ErrInvalidId
+	}
+
+	return ms, seq, nil
+}
@@ -0,0 +1,155 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/config"
+)
+
+func TestAfter(t *testing.T) {
+	tests := []struct {
+		name      string
+		id, other string
+		want      bool
+	}{
+		{name: "later ms", id: "1700000000001-0", other: "1700000000000-5", want: true},
+		{name: "earlier ms", id: "1700000000000-5", other: "1700000000001-0", want: false},
+		{name: "same ms, higher seq", id: "1700000000000-2", other: "1700000000000-1", want: true},
+		{name: "same ms, lower seq", id: "1700000000000-1", other: "1700000000000-2", want: false},
+		{name: "same id", id: "1700000000000-1", other: "1700000000000-1", want: false},
+		{name: "seq compared as numbers", id: "1700000000000-10", other: "1700000000000-9", want: true},
+		{name: "malformed id", id: "soon", other: "1700000000000-1", want: true},
+		{name: "malformed other", id: "1700000000000-1", other: "1700000000000-x", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := After(tt.id, tt.other); got != tt.want {
+				t.Errorf("After(%q, %q) = %v, want %v", tt.id, tt.other, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseId(t *testing.T) {
+	tests := []struct {
+		id      string
+		ms, seq uint64
+		wantErr bool
+	}{
+		{id: "1700000000000-0", ms: 1700000000000, seq: 0},
+		{id: "0-1", ms: 0, seq: 1},
+		{id: "1700000000000", wantErr: true},
+		{id: "", wantErr: true},
+		{id: "-1", wantErr: true},
+		{id: "1700000000000-", wantErr: true},
+		{id: "a-1", wantErr: true},
+		{id: "1--1", wantErr: true},
+		{id: "1-2-3", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		ms, seq, err := parseId(tt.id)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidId) {
+				t.Errorf("parseId(%q) error = %v, want ErrInvalidId", tt.id, err)
+			}
+			continue
+		}
+
+		if err != nil || ms != tt.ms || seq != tt.seq {
+			t.Errorf("parseId(%q) = %d, %d, %v, want %d, %d", tt.id, ms, seq, err, tt.ms, tt.seq)
+		}
+	}
+}
+
+type countingLogger struct {
+	errors atomic.Int32
+}
+
+func (l *countingLogger) Errorf(format string, args ...any) {
+	l.errors.Add(1)
+}
+
+func newTestBroker(addr string, logger Logger) *Broker {
+	client := redis.NewClient(&redis.Options{Addr: addr, MaxRetries: -1})
+	return NewBroker(*client, time.Second, config.Events{
+		History: 10, HistoryTTL: time.Minute, MaxConnections: 10, MaxConnectionsPerClient: 10,
+	}, logger)
+}
+
+func TestBrokerRunRetriesUntilDone(t *testing.T) {
+	mr := miniredis.RunT(t)
+	addr := mr.Addr()
+	mr.Close()
+
+	logger := &countingLogger{}
+	b := newTestBroker(addr, logger)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	if err := b.Run(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Run() error = %v, want it to keep retrying until ctx is done", err)
+	}
+	if logger.errors.Load() < 2 {
+		t.Errorf("Run() subscribed %d times, want it to retry", logger.errors.Load()+1)
+	}
+}
+
+func TestBrokerRunResubscribes(t *testing.T) {
+	mr := miniredis.RunT(t)
+	b := newTestBroker(mr.Addr(), &countingLogger{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- b.Run(ctx) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	sub, err := b.Subscribe(MovieTopic(1), "client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	// received publishes until the broker, subscribing in the background,
+	// hands an event to sub.
+	received := func() bool {
+		deadline := time.After(5 * time.Second)
+		for {
+			if err := b.Publish(ctx, MovieTopic(1), "review.created", nil); err != nil {
+				t.Logf("Publish() error = %v", err)
+			}
+
+			select {
+			case <-sub.C:
+				return true
+			case <-deadline:
+				return false
+			case <-time.After(50 * time.Millisecond):
+			}
+		}
+	}
+
+	if !received() {
+		t.Fatal("no event before Redis restarted")
+	}
+
+	mr.Close()
+	if err := mr.Restart(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !received() {
+		t.Error("no event after Redis restarted")
+	}
+}
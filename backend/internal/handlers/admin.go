@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Admin only lets through users whose email is in emails. It must run after
+// Protection.
+func Admin(emails []string) echo.MiddlewareFunc {
+	admins := make(map[string]struct{}, len(emails))
+	for _, email := range emails {
+		admins[strings.ToLower(email)] = struct{}{}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if _, ok := admins[strings.ToLower(MustGetUser(c).Email)]; !ok {
+				return echo.ErrForbidden.SetInternal(errors.New("Admin: user is not an admin"))
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/feed"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/movie"
+	"golang.org/x/sync/errgroup"
+)
+
+type (
+	MovieCatalog interface {
+		Save(ctx context.Context, movie movie.Movie) error
+		ReadMany(ctx context.Context, movieIds []int32) (map[int32]movie.Movie, error)
+	}
+
+	CatalogClient interface {
+		GetMovie(ctx context.Context, id int32, locale movie.Locale) (movie.Movie, error)
+		GetImageConfig(ctx context.Context) (movie.ImageConfig, error)
+	}
+
+	// movieLookup finds the movies reviews are about, in the local catalog
+	// first and on TMDB for the ones it doesn't have yet.
+	movieLookup struct {
+		catalog MovieCatalog
+		client  CatalogClient
+	}
+)
+
+const movieLookupConcurrency = 4
+
+func newMovieLookup(catalog MovieCatalog, client CatalogClient) movieLookup {
+	return movieLookup{catalog, client}
+}
+
+// attach sets the movie of every review it can find. Movies missing from
+// both the catalog and TMDB are left unset.
+func (l movieLookup) attach(ctx context.Context, reviews movie.Reviews) error {
+	ids := make([]int32, 0, len(reviews))
+	for _, r := range reviews {
+		ids = append(ids, r.MovieId)
+	}
+
+	movies, err := l.find(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for i, r := range reviews {
+		if m, ok := movies[r.MovieId]; ok {
+			reviews[i].Movie = &m
+		}
+	}
+
+	return nil
+}
+
+// attachItems is attach for feed items.
+func (l movieLookup) attachItems(ctx context.Context, items []feed.Item) error {
+	ids := make([]int32, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.MovieId)
+	}
+
+	movies, err := l.find(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for i, item := range items {
+		if m, ok := movies[item.MovieId]; ok {
+			items[i].Movie = &m
+		}
+	}
+
+	return nil
+}
+
+// findIn returns the movies in ids localized for locale. The catalog only
+// holds the default locale, others come from TMDB.
+func (l movieLookup) findIn(ctx context.Context, ids []int32, locale movie.Locale) (map[int32]movie.Movie, error) {
+	if locale == movie.DefaultLocale {
+		return l.find(ctx, ids)
+	}
+	return l.fetch(ctx, ids, locale), nil
+}
+
+// find returns the movies in ids, keyed by id, saving the ones fetched from
+// TMDB to the catalog.
+func (l movieLookup) find(ctx context.Context, ids []int32) (map[int32]movie.Movie, error) {
+	movies, err := l.catalog.ReadMany(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	if config, err := l.client.GetImageConfig(ctx); err == nil {
+		for id, m := range movies {
+			m.ResolveImages(config)
+			movies[id] = m
+		}
+	}
+
+	missing := make(map[int32]bool)
+	for _, id := range ids {
+		if _, ok := movies[id]; !ok {
+			missing[id] = true
+		}
+	}
+	maps.Copy(movies, l.fetch(ctx, slices.Collect(maps.Keys(missing)), movie.DefaultLocale))
+
+	return movies, nil
+}
+
+// fetch gets the movies in ids from TMDB, leaving out the ones it fails to
+// get. Default locale movies are saved to the catalog.
+func (l movieLookup) fetch(ctx context.Context, ids []int32, locale movie.Locale) map[int32]movie.Movie {
+	movies := make(map[int32]movie.Movie, len(ids))
+
+	var (
+		mu sync.Mutex
+		g  errgroup.Group
+	)
+	g.SetLimit(movieLookupConcurrency)
+
+	for _, id := range ids {
+		g.Go(func() error {
+			m, err := l.client.GetMovie(ctx, id, locale)
+			if err != nil {
+				return nil
+			}
+			if locale == movie.DefaultLocale {
+				_ = l.catalog.Save(ctx, m)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			movies[id] = m
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return movies
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/config"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/movie"
+)
+
+type (
+	ChartStore interface {
+		ReadTopRated(ctx context.Context, since time.Time, minVotes int32, globalMean float64, limit int32) ([]movie.ChartEntry, error)
+		ReadMostReviewed(ctx context.Context, since time.Time, limit int32) ([]movie.ChartEntry, error)
+	}
+
+	// chartHandler ranks movies by their local reviews.
+	chartHandler struct {
+		store  ChartStore
+		movies movieLookup
+		config config.Ratings
+	}
+)
+
+func NewChartHandler(store ChartStore, catalog MovieCatalog, client CatalogClient, c config.Ratings) *chartHandler {
+	return &chartHandler{store, newMovieLookup(catalog, client), c}
+}
+
+func (h chartHandler) RegisterRoutes(g *echo.Group) {
+	g.GET("/top-rated", h.getTopRated)
+	g.GET("/most-reviewed", h.getMostReviewed)
+}
+
+func (h chartHandler) getTopRated(c echo.Context) error {
+	since, limit, err := chartParams(c)
+	if err != nil {
+		return err
+	}
+
+	locale, err := getLocale(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+
+	chart, err := h.store.ReadTopRated(ctx, since, int32(h.config.MinVotes), h.config.GlobalMean, limit)
+	if err != nil {
+		return err
+	}
+
+	if err := h.attach(ctx, chart, locale); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, chart)
+}
+
+func (h chartHandler) getMostReviewed(c echo.Context) error {
+	since, limit, err := chartParams(c)
+	if err != nil {
+		return err
+	}
+
+	locale, err := getLocale(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+
+	chart, err := h.store.ReadMostReviewed(ctx, since, limit)
+	if err != nil {
+		return err
+	}
+
+	if err := h.attach(ctx, chart, locale); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, chart)
+}
+
+func (h chartHandler) attach(ctx context.Context, chart []movie.ChartEntry, locale movie.Locale) error {
+	ids := make([]int32, 0, len(chart))
+	for _, entry := range chart {
+		ids = append(ids, entry.MovieId)
+	}
+
+	movies, err := h.movies.findIn(ctx, ids, locale)
+	if err != nil {
+		return err
+	}
+
+	for i, entry := range chart {
+		if m, ok := movies[entry.MovieId]; ok {
+			chart[i].Movie = &m
+		}
+	}
+
+	return nil
+}
+
+// chartParams reads the window query param, all time by default, and the
+// limit, 20 by default and 100 at most.
+func chartParams(c echo.Context) (time.Time, int32, error) {
+	window, err := movie.ParseWindow(c.QueryParam("window"))
+	if err != nil {
+		return time.Time{}, 0, echo.NewHTTPError(http.StatusBadRequest, "Not a valid window").SetInternal(err)
+	}
+
+	limit, err := queryInt32(c, "limit", 20, 1, 100)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	return window.Since(time.Now()), limit, nil
+}
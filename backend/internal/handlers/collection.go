@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/config"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/movie"
+)
+
+type (
+	CollectionClient interface {
+		GetCollection(ctx context.Context, id int32, locale movie.Locale) (movie.Collection, error)
+	}
+
+	collectionHandler struct {
+		client     CollectionClient
+		movieStore MovieStore
+		ratings    config.Ratings
+	}
+)
+
+func NewCollectionHandler(client CollectionClient, movieStore MovieStore, ratings config.Ratings) *collectionHandler {
+	return &collectionHandler{client, movieStore, ratings}
+}
+
+func (h collectionHandler) RegisterRoutes(g *echo.Group) {
+	g.GET("/:id", h.getCollection)
+}
+
+func (h collectionHandler) getCollection(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Not a valid collection id").SetInternal(err)
+	}
+
+	locale, err := getLocale(c)
+	if err != nil {
+		return err
+	}
+
+	collection, err := h.client.GetCollection(c.Request().Context(), int32(id), locale)
+	if err != nil {
+		return movieAPIError(c, err)
+	}
+
+	ids := make([]int32, 0, len(collection.Parts))
+	for _, part := range collection.Parts {
+		ids = append(ids, part.Id)
+	}
+
+	ratings, err := readRatings(c.Request().Context(), h.movieStore, h.ratings, ids)
+	if err != nil {
+		return err
+	}
+
+	collection.SortPartsByReleaseDate()
+	collection.SetLocalRatings(ratings)
+
+	return c.JSON(http.StatusOK, collection)
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/cookies"
+)
+
+const csrfContextKey = "csrf"
+
+// CSRF returns a double submit cookie middleware. Safe requests get a token
+// cookie; state changing requests must echo it in the X-CSRF-Token header.
+func CSRF(p cookies.Policy) echo.MiddlewareFunc {
+	return middleware.CSRFWithConfig(middleware.CSRFConfig{
+		TokenLookup:    "header:" + echo.HeaderXCSRFToken,
+		ContextKey:     csrfContextKey,
+		CookieName:     p.Name("csrf"),
+		CookieDomain:   p.Domain,
+		CookiePath:     p.Path,
+		CookieSecure:   p.Secure,
+		CookieHTTPOnly: true,
+		CookieSameSite: p.SameSite,
+	})
+}
+
+func getCSRFToken(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]string{"token": c.Get(csrfContextKey).(string)})
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/events"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/user"
+)
+
+type (
+	EventBroker interface {
+		Subscribe(topic, client string) (*events.Subscription, error)
+		Since(ctx context.Context, topic, lastId string) ([]events.Event, error)
+	}
+
+	eventsHandler struct {
+		broker    EventBroker
+		heartbeat time.Duration
+	}
+)
+
+// eventsRetry is how long clients wait before reconnecting.
+const eventsRetry = 3 * time.Second
+
+func NewEventsHandler(broker EventBroker, heartbeat time.Duration) *eventsHandler {
+	return &eventsHandler{broker, heartbeat}
+}
+
+func (h eventsHandler) RegisterRoutes(movies, users *echo.Group, authentication, protection echo.MiddlewareFunc) {
+	movies.GET("/:id/events", h.getMovieEvents, authentication)
+	users.GET("/me/events", h.getUserEvents, protection)
+}
+
+func (h eventsHandler) getMovieEvents(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Not a valid movie id").SetInternal(err)
+	}
+
+	return h.stream(c, events.MovieTopic(int32(id)))
+}
+
+func (h eventsHandler) getUserEvents(c echo.Context) error {
+	return h.stream(c, events.UserTopic(MustGetUser(c).Id))
+}
+
+// stream sends the events of topic as server-sent events until the client
+// goes away. Events missed since Last-Event-ID are replayed first.
+func (h eventsHandler) stream(c echo.Context, topic string) error {
+	client := "ip:" + c.RealIP()
+	if u, ok := c.Get("user").(user.User); ok {
+		client = fmt.Sprintf("user:%d", u.Id)
+	}
+
+	sub, err := h.broker.Subscribe(topic, client)
+	if err != nil {
+		if errors.Is(err, events.ErrTooManyConnections) {
+			return echo.NewHTTPError(http.StatusTooManyRequests, "Too many open event streams").SetInternal(err)
+		}
+		return err
+	}
+	defer sub.Close()
+
+	ctx := c.Request().Context()
+
+	// Subscribing before reading the history means no event is missed in
+	// between, the ones seen twice are skipped by id.
+	var missed []events.Event
+	lastId := c.Request().Header.Get("Last-Event-ID")
+	if lastId != "" {
+		if missed, err = h.broker.Since(ctx, topic, lastId); err != nil {
+			c.Logger().Error("Events: ", err)
+		}
+	}
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, "text/event-stream")
+	header.Set(echo.HeaderCacheControl, "no-cache")
+	header.Set(echo.HeaderConnection, "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	c.Response().WriteHeader(http.StatusOK)
+
+	w := c.Response()
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", eventsRetry.Milliseconds()); err != nil {
+		return nil
+	}
+
+	send := func(e events.Event) error {
+		if lastId != "" && !events.After(e.Id, lastId) {
+			return nil
+		}
+		lastId = e.Id
+
+		_, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.Id, e.Type, e.Data)
+		return err
+	}
+
+	for _, e := range missed {
+		if err := send(e); err != nil {
+			return nil
+		}
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+		case e, ok := <-sub.C:
+			if !ok {
+				return nil
+			}
+			if err := send(e); err != nil {
+				return nil
+			}
+		}
+		w.Flush()
+	}
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/feed"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/notification"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/user"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/stores"
+)
+
+type (
+	FollowStore interface {
+		Follow(ctx context.Context, followerId, followeeId int32) (bool, error)
+		Unfollow(ctx context.Context, followerId, followeeId int32) error
+		ReadCounts(ctx context.Context, userId int32) (user.FollowCounts, error)
+		ReadFollowers(ctx context.Context, userId int32, cursor feed.Cursor, limit int32) ([]user.Follow, error)
+		ReadFollowing(ctx context.Context, userId int32, cursor feed.Cursor, limit int32) ([]user.Follow, error)
+		ReadFeed(ctx context.Context, userId int32, cursor feed.ItemCursor, limit int32) ([]feed.Item, error)
+	}
+
+	UserFinder interface {
+		ReadByUsername(ctx context.Context, username string) (user.User, error)
+	}
+
+	followHandler struct {
+		followStore FollowStore
+		users       UserFinder
+		notifier    Notifier
+		movies      movieLookup
+	}
+
+	followPage struct {
+		feed.Page[user.Follow]
+		Count int64 `json:"count"`
+	}
+)
+
+func NewFollowHandler(followStore FollowStore, users UserFinder, notifier Notifier, catalog MovieCatalog, client CatalogClient) *followHandler {
+	return &followHandler{followStore, users, notifier, newMovieLookup(catalog, client)}
+}
+
+func (h followHandler) RegisterRoutes(g *echo.Group, protection, csrf echo.MiddlewareFunc) {
+	g.GET("/me/feed", h.getFeed, protection)
+	g.GET("/:username/followers", h.getFollowers)
+	g.GET("/:username/following", h.getFollowing)
+	g.POST("/:username/follow", h.follow, protection, csrf)
+	g.DELETE("/:username/follow", h.unfollow, protection, csrf)
+}
+
+func (h followHandler) follow(c echo.Context) error {
+	followee, err := h.readUser(c)
+	if err != nil {
+		return err
+	}
+
+	follower := MustGetUser(c)
+	if follower.Id == followee.Id {
+		return echo.NewHTTPError(http.StatusBadRequest, "You can't follow yourself")
+	}
+
+	followed, err := h.followStore.Follow(c.Request().Context(), follower.Id, followee.Id)
+	if err != nil {
+		return err
+	}
+
+	if followed {
+		if err := h.notifier.Notify(c.Request().Context(), notification.NewFollow(followee.Id, follower)); err != nil {
+			c.Logger().Error("Notify: ", err)
+		}
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h followHandler) unfollow(c echo.Context) error {
+	followee, err := h.readUser(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.followStore.Unfollow(c.Request().Context(), MustGetUser(c).Id, followee.Id); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h followHandler) getFollowers(c echo.Context) error {
+	return h.getFollows(c, h.followStore.ReadFollowers, func(c user.FollowCounts) int64 { return c.Followers })
+}
+
+func (h followHandler) getFollowing(c echo.Context) error {
+	return h.getFollows(c, h.followStore.ReadFollowing, func(c user.FollowCounts) int64 { return c.Following })
+}
+
+func (h followHandler) getFollows(
+	c echo.Context,
+	read func(ctx context.Context, userId int32, cursor feed.Cursor, limit int32) ([]user.Follow, error),
+	count func(user.FollowCounts) int64,
+) error {
+	u, err := h.readUser(c)
+	if err != nil {
+		return err
+	}
+
+	cursor, limit, err := feedParams(c)
+	if err != nil {
+		return err
+	}
+
+	follows, err := read(c.Request().Context(), u.Id, cursor, limit+1)
+	if err != nil {
+		return err
+	}
+
+	counts, err := h.followStore.ReadCounts(c.Request().Context(), u.Id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, followPage{
+		Page:  feed.NewPage(follows, int(limit), followCursor),
+		Count: count(counts),
+	})
+}
+
+func (h followHandler) getFeed(c echo.Context) error {
+	cursor, limit, err := itemFeedParams(c)
+	if err != nil {
+		return err
+	}
+
+	items, err := h.followStore.ReadFeed(c.Request().Context(), MustGetUser(c).Id, cursor, limit+1)
+	if err != nil {
+		return err
+	}
+
+	page := feed.NewPage(items, int(limit), feed.Item.Cursor)
+	if err := h.movies.attachItems(c.Request().Context(), page.Results); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, page)
+}
+
+func (h followHandler) readUser(c echo.Context) (user.User, error) {
+	u, err := h.users.ReadByUsername(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		if errors.Is(err, stores.ErrNotFound) {
+			return u, echo.NewHTTPError(http.StatusNotFound, "user not found").SetInternal(err)
+		}
+		return u, err
+	}
+
+	return u, nil
+}
+
+func followCursor(f user.Follow) feed.Cursor {
+	return feed.Cursor{CreatedAt: f.FollowedAt, Id: f.User.Id}
+}
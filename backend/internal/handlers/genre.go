@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/movie"
+)
+
+type (
+	GenreClient interface {
+		GetGenres(ctx context.Context, locale movie.Locale) (movie.Genres, error)
+	}
+
+	genreHandler struct {
+		client GenreClient
+	}
+)
+
+func NewGenreHandler(client GenreClient) *genreHandler {
+	return &genreHandler{client}
+}
+
+func (h genreHandler) RegisterRoutes(g *echo.Group) {
+	g.GET("", h.getGenres)
+}
+
+func (h genreHandler) getGenres(c echo.Context) error {
+	locale, err := getLocale(c)
+	if err != nil {
+		return err
+	}
+
+	genres, err := h.client.GetGenres(c.Request().Context(), locale)
+	if err != nil {
+		return movieAPIError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, genres)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"cmp"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/movie"
+	"golang.org/x/text/language"
+)
+
+// getLocale reads the locale from the lang or language query params, falling
+// back to the Accept-Language header and then to movie.DefaultLocale.
+func getLocale(c echo.Context) (movie.Locale, error) {
+	if lang := cmp.Or(c.QueryParam("lang"), c.QueryParam("language")); lang != "" {
+		tag, err := language.Parse(lang)
+		if err != nil {
+			return movie.Locale{}, echo.NewHTTPError(http.StatusBadRequest, "Invalid lang").SetInternal(err)
+		}
+		return tagToLocale(tag), nil
+	}
+
+	tags, _, err := language.ParseAcceptLanguage(c.Request().Header.Get("Accept-Language"))
+	if err != nil || len(tags) == 0 || tags[0] == language.Und {
+		return movie.DefaultLocale, nil
+	}
+
+	return tagToLocale(tags[0]), nil
+}
+
+// getSearchConfig returns the text search configuration for lang, an IETF
+// language tag, or fallback when lang is empty.
+func getSearchConfig(lang, fallback string) (string, error) {
+	if lang == "" {
+		return fallback, nil
+	}
+
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "Invalid language").SetInternal(err)
+	}
+
+	base, _ := tag.Base()
+	return movie.SearchConfig(base.String()), nil
+}
+
+func tagToLocale(tag language.Tag) movie.Locale {
+	base, _ := tag.Base()
+	region, confidence := tag.Region()
+	if confidence == language.No {
+		return movie.Locale{Language: base.String()}
+	}
+
+	return movie.Locale{Language: base.String() + "-" + region.String(), Region: region.String()}
+}
@@ -1,30 +1,56 @@
 package handlers
 
 import (
+	"cmp"
 	"context"
 	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/labstack/echo/v4"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/config"
 	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/movie"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/person"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/search"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/movieapi"
 	"github.com/rodrigoaraujo46/flickmeter/backend/internal/stores"
+	"golang.org/x/text/language"
 )
 
 type (
 	MovieClient interface {
-		GetTrending(ctx context.Context, weekly bool) (movie.Movies, error)
-		GetMovie(ctx context.Context, id int32) (movie.Movie, error)
-		GetVideos(ctx context.Context, id int32) (movie.Videos, error)
-		Search(ctx context.Context, query string) (movie.Movies, error)
+		GetTrending(ctx context.Context, weekly bool, locale movie.Locale) (movie.Movies, error)
+		GetMovie(ctx context.Context, id int32, locale movie.Locale) (movie.Movie, error)
+		GetVideos(ctx context.Context, id int32, locale movie.Locale) (movie.Videos, error)
+		GetCredits(ctx context.Context, id int32, locale movie.Locale) (movie.Credits, error)
+		SearchMovies(ctx context.Context, p search.Params, locale movie.Locale) (search.Page[movie.Movie], error)
+		SearchPeople(ctx context.Context, p search.Params, locale movie.Locale) (search.Page[person.Person], error)
+		SearchCollections(ctx context.Context, p search.Params, locale movie.Locale) (search.Page[movie.Collection], error)
+		SearchMulti(ctx context.Context, p search.Params, locale movie.Locale) (search.Page[search.Result], error)
+		Discover(ctx context.Context, d search.Discover, locale movie.Locale) (search.Page[movie.Movie], error)
+		GetGenres(ctx context.Context, locale movie.Locale) (movie.Genres, error)
+		GetImageConfig(ctx context.Context) (movie.ImageConfig, error)
 	}
 
 	MovieStore interface {
-		ReadAverageRating(ctx context.Context, movieId int32) (float64, error)
+		ReadWeightedRatings(ctx context.Context, movieIds []int32, minVotes int32, globalMean float64) (map[int32]float64, error)
+	}
+
+	CatalogStore interface {
+		Save(ctx context.Context, movie movie.Movie) error
+		Read(ctx context.Context, movieId int32) (movie.Movie, error)
+		ReadMany(ctx context.Context, movieIds []int32) (map[int32]movie.Movie, error)
+		ReadStale(ctx context.Context, before time.Time, limit int32) ([]int32, error)
 	}
 
 	ReviewStore interface {
-		Create(ctx context.Context, review movie.Review) error
+		Create(ctx context.Context, review movie.Review) (movie.Review, error)
 		ReadReviews(ctx context.Context, movieId, page int32) (movie.Reviews, error)
 		ReadUserReview(ctx context.Context, movieId, userId int32) (movie.Review, error)
 		ReadReview(ctx context.Context, id int32) (movie.Review, error)
@@ -32,81 +58,346 @@ type (
 		Delete(ctx context.Context, id int32) error
 	}
 
+	TrendingStore interface {
+		Read(ctx context.Context, weekly bool, limit int32) ([]int32, error)
+	}
+
+	// TasteInvalidator drops the taste comparisons cached for a user, whose
+	// reviews changed.
+	TasteInvalidator interface {
+		Invalidate(ctx context.Context, userId int32) error
+	}
+
 	movieHandler struct {
-		client      MovieClient
-		movieStore  MovieStore
-		reviewStore ReviewStore
+		client       MovieClient
+		movieStore   MovieStore
+		catalogStore CatalogStore
+		reviewStore  ReviewStore
+		taste        TasteInvalidator
+		trending     TrendingStore
+		ratings      config.Ratings
+		movies       movieLookup
 	}
 )
 
-func NewMovieHandler(movieClient MovieClient, movieStore MovieStore, reviewStore ReviewStore) *movieHandler {
-	return &movieHandler{movieClient, movieStore, reviewStore}
+const (
+	catalogMaxAge       = 24 * time.Hour
+	catalogRefreshBatch = 50
+
+	trendingLocal = "local"
+	trendingTMDB  = "tmdb"
+	trendingBlend = "blend"
+	// trendingSize matches the size of a page of TMDB's trending movies.
+	trendingSize = 20
+	// blendK damps the lead of the top ranks when blending rankings.
+	blendK = 60
+)
+
+func NewMovieHandler(movieClient MovieClient, movieStore MovieStore, catalogStore CatalogStore, reviewStore ReviewStore, taste TasteInvalidator, trending TrendingStore, ratings config.Ratings) *movieHandler {
+	return &movieHandler{movieClient, movieStore, catalogStore, reviewStore, taste, trending, ratings,
+		newMovieLookup(catalogStore, movieClient)}
 }
 
-func (h movieHandler) RegisterRoutes(g *echo.Group, protection echo.MiddlewareFunc) {
+func (h movieHandler) RegisterRoutes(g *echo.Group, authentication, protection, csrf echo.MiddlewareFunc, limits RateLimits) {
 	g.GET("/:id", h.getMovie)
 	g.GET("/:id/videos", h.getVideos)
+	g.GET("/:id/credits", h.getCredits)
 	g.GET("/trending", h.getTrending)
-	g.GET("/search", h.searchMovies)
+	g.GET("/search", h.search, authentication, limits.Search)
+	g.GET("/discover", h.discover, authentication, limits.Search)
 	g.GET("/:id/reviews", h.getReviews)
 
 	g.GET("/:id/reviews/me", h.getUserReview, protection)
-	g.POST("/:id/reviews", h.postReview, protection)
-	g.PATCH("/:id/reviews/:reviewid", h.patchReview, protection)
-	g.DELETE("/:id/reviews/:reviewid", h.deleteReview, protection)
+	g.POST("/:id/reviews", h.postReview, protection, csrf, limits.Reviews)
+	g.PATCH("/:id/reviews/:reviewid", h.patchReview, protection, csrf, limits.Reviews)
+	g.DELETE("/:id/reviews/:reviewid", h.deleteReview, protection, csrf, limits.Reviews)
 }
 
 func (h movieHandler) getTrending(c echo.Context) error {
 	weekly, _ := strconv.ParseBool(c.QueryParam("weekly"))
 
-	movies, err := h.client.GetTrending(c.Request().Context(), weekly)
+	locale, err := getLocale(c)
 	if err != nil {
 		return err
 	}
 
+	ctx := c.Request().Context()
+
+	var movies movie.Movies
+	switch c.QueryParam("source") {
+	case "", trendingTMDB:
+		if movies, err = h.client.GetTrending(ctx, weekly, locale); err != nil {
+			return movieAPIError(c, err)
+		}
+	case trendingLocal:
+		if movies, err = h.localTrending(ctx, weekly, locale); err != nil {
+			return err
+		}
+	case trendingBlend:
+		local, err := h.localTrending(ctx, weekly, locale)
+		if err != nil {
+			return err
+		}
+
+		tmdb, err := h.client.GetTrending(ctx, weekly, locale)
+		if err != nil {
+			c.Logger().Error("GetTrending: ", err)
+		}
+
+		movies = blendRankings(trendingSize, local, tmdb)
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "Not a valid source, must be local, tmdb or blend")
+	}
+
+	ids := make([]int32, 0, len(movies))
+	for _, movie := range movies {
+		ids = append(ids, movie.Id)
+	}
+
+	ratings, err := readRatings(ctx, h.movieStore, h.ratings, ids)
+	if err != nil {
+		c.Logger().Error("ReadWeightedRatings: ", err)
+	}
 	for i, movie := range movies {
-		movies[i].VoteAverage, _ = h.movieStore.ReadAverageRating(c.Request().Context(), movie.Id)
+		movies[i].VoteAverage = ratings[movie.Id]
 	}
 
 	return c.JSON(http.StatusOK, movies)
 }
 
+// readRatings returns the weighted rating of every movie in ids with a
+// review, with the prior in c.
+func readRatings(ctx context.Context, store MovieStore, c config.Ratings, ids []int32) (map[int32]float64, error) {
+	return store.ReadWeightedRatings(ctx, ids, int32(c.MinVotes), c.GlobalMean)
+}
+
+// localTrending returns the movies trending on FlickMeter in locale, from
+// the ranking the trending worker keeps.
+func (h movieHandler) localTrending(ctx context.Context, weekly bool, locale movie.Locale) (movie.Movies, error) {
+	ids, err := h.trending.Read(ctx, weekly, trendingSize)
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := h.movies.findIn(ctx, ids, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	movies := make(movie.Movies, 0, len(ids))
+	for _, id := range ids {
+		if m, ok := found[id]; ok {
+			movies = append(movies, m)
+		}
+	}
+
+	return movies, nil
+}
+
+// blendRankings merges rankings with reciprocal rank fusion, every movie
+// scoring 1/(blendK+rank) in each ranking it is in, and keeps the best size.
+func blendRankings(size int, rankings ...movie.Movies) movie.Movies {
+	scores := make(map[int32]float64)
+	var movies movie.Movies
+	for _, ranking := range rankings {
+		for rank, m := range ranking {
+			if _, ok := scores[m.Id]; !ok {
+				movies = append(movies, m)
+			}
+			scores[m.Id] += 1 / float64(blendK+rank+1)
+		}
+	}
+
+	slices.SortStableFunc(movies, func(a, b movie.Movie) int {
+		return cmp.Compare(scores[b.Id], scores[a.Id])
+	})
+
+	return movies[:min(size, len(movies))]
+}
+
 func (h movieHandler) getMovie(c echo.Context) error {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "Not a valid movie id").SetInternal(err)
 	}
 
-	movie, err := h.client.GetMovie(c.Request().Context(), int32(id))
+	locale, err := getLocale(c)
 	if err != nil {
 		return err
 	}
+	// The catalog is kept in the default locale, like its refreshes.
+	catalogLocale := locale == movie.DefaultLocale
+
+	movie, err := h.client.GetMovie(c.Request().Context(), int32(id), locale)
+	switch {
+	case err == nil:
+		if catalogLocale {
+			if err := h.catalogStore.Save(c.Request().Context(), movie); err != nil {
+				c.Logger().Error("Catalog: ", err)
+			}
+		}
+	case errors.Is(err, movieapi.ErrUnavailable), errors.Is(err, movieapi.ErrRateLimited):
+		movie, err = h.readCatalogMovie(c.Request().Context(), int32(id), err)
+		if err != nil {
+			return movieAPIError(c, err)
+		}
+	default:
+		return movieAPIError(c, err)
+	}
 
-	movie.VoteAverage, err = h.movieStore.ReadAverageRating(c.Request().Context(), int32(id))
+	ratings, err := readRatings(c.Request().Context(), h.movieStore, h.ratings, []int32{int32(id)})
 	if err != nil {
 		return err
 	}
+	movie.VoteAverage = ratings[int32(id)]
 
 	return c.JSON(http.StatusOK, movie)
 }
 
+// readCatalogMovie falls back to the local catalog when TMDB can't serve a
+// movie, returning apiErr if the movie was never saved.
+func (h movieHandler) readCatalogMovie(ctx context.Context, id int32, apiErr error) (movie.Movie, error) {
+	m, err := h.catalogStore.Read(ctx, id)
+	if err != nil {
+		return m, apiErr
+	}
+
+	if config, err := h.client.GetImageConfig(ctx); err == nil {
+		m.ResolveImages(config)
+	}
+
+	return m, nil
+}
+
+// RefreshCatalog refetches a batch of catalog movies not saved for a while,
+// so the local copy stays close to TMDB.
+func (h movieHandler) RefreshCatalog(ctx context.Context) error {
+	ids, err := h.catalogStore.ReadStale(ctx, time.Now().Add(-catalogMaxAge), catalogRefreshBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		m, err := h.client.GetMovie(ctx, id, movie.DefaultLocale)
+		if errors.Is(err, movieapi.ErrNotFound) {
+			// Keep the last known copy, but push it to the back of the queue.
+			if m, err = h.catalogStore.Read(ctx, id); err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+
+		if err := h.catalogStore.Save(ctx, m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (h movieHandler) getVideos(c echo.Context) error {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "Not a valid movid id").SetInternal(err)
 	}
 
-	videos, err := h.client.GetVideos(c.Request().Context(), int32(id))
+	filter, err := parseVideoFilter(c)
 	if err != nil {
 		return err
 	}
 
-	videos.FilterTrailersAndTeasersOnYT()
+	locale, err := getLocale(c)
+	if err != nil {
+		return err
+	}
+
+	videos, err := h.client.GetVideos(c.Request().Context(), int32(id), locale)
+	if err != nil {
+		return movieAPIError(c, err)
+	}
+	videos.Filter(filter)
+
+	if len(videos) == 0 && locale != movie.DefaultLocale {
+		videos, err = h.client.GetVideos(c.Request().Context(), int32(id), movie.DefaultLocale)
+		if err != nil {
+			return movieAPIError(c, err)
+		}
+		videos.Filter(filter)
+	}
+
 	videos.SortByRelevance()
+	videos.SetURLs()
+
+	if videos == nil {
+		videos = movie.Videos{}
+	}
 
 	return c.JSON(http.StatusOK, videos)
 }
 
+// parseVideoFilter reads comma separated type and site lists and official,
+// which may be true, false or any. Missing params keep their defaults.
+func parseVideoFilter(c echo.Context) (movie.VideoFilter, error) {
+	filter := movie.DefaultVideoFilter()
+
+	split := func(value string) (values []string) {
+		for v := range strings.SplitSeq(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		return values
+	}
+
+	if types := c.QueryParam("type"); types != "" {
+		filter.Types = split(types)
+	}
+
+	if sites := c.QueryParam("site"); sites != "" {
+		filter.Sites = split(sites)
+		for _, site := range filter.Sites {
+			if !slices.ContainsFunc(movie.VideoSites, func(s string) bool { return strings.EqualFold(s, site) }) {
+				return filter, echo.NewHTTPError(http.StatusBadRequest,
+					"Invalid site, must be one of "+strings.Join(movie.VideoSites, ", "))
+			}
+		}
+	}
+
+	switch official := c.QueryParam("official"); official {
+	case "":
+	case "any":
+		filter.Official = nil
+	default:
+		b, err := strconv.ParseBool(official)
+		if err != nil {
+			return filter, echo.NewHTTPError(http.StatusBadRequest, "Invalid official, must be true, false or any").SetInternal(err)
+		}
+		filter.Official = &b
+	}
+
+	return filter, nil
+}
+
+func (h movieHandler) getCredits(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Not a valid movie id").SetInternal(err)
+	}
+
+	locale, err := getLocale(c)
+	if err != nil {
+		return err
+	}
+
+	credits, err := h.client.GetCredits(c.Request().Context(), int32(id), locale)
+	if err != nil {
+		return movieAPIError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, credits.Grouped())
+}
+
 func (h movieHandler) getReviews(c echo.Context) error {
 	movieId, err := strconv.ParseInt(c.Param("id"), 10, 32)
 	if err != nil {
@@ -154,23 +445,30 @@ func (h movieHandler) postReview(c echo.Context) error {
 	}
 
 	f := &struct {
-		Title  string `json:"title"`
-		Rating int32  `json:"rating"`
-		Review string `json:"review"`
+		Title    string `json:"title"`
+		Rating   int32  `json:"rating"`
+		Review   string `json:"review"`
+		Language string `json:"language"`
 	}{}
 	if err := c.Bind(f); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest).SetInternal(err)
 	}
 
 	review := movie.NewReview(f.Title, f.Rating, f.Review)
-	review.UserId = MustGetUser(c).Id
+	if review.Language, err = getSearchConfig(f.Language, "simple"); err != nil {
+		return err
+	}
+	review.User = MustGetUser(c)
+	review.UserId = review.User.Id
 	review.MovieId = int32(movieId)
 
-	if err := h.reviewStore.Create(c.Request().Context(), *review); err != nil {
+	created, err := h.reviewStore.Create(c.Request().Context(), *review)
+	if err != nil {
 		return err
 	}
+	h.invalidateTaste(c, created.UserId)
 
-	return c.JSON(http.StatusCreated, review)
+	return c.JSON(http.StatusCreated, created)
 }
 
 func (h movieHandler) patchReview(c echo.Context) error {
@@ -195,9 +493,10 @@ func (h movieHandler) patchReview(c echo.Context) error {
 	}
 
 	f := &struct {
-		Title  string `json:"title"`
-		Rating int32  `json:"rating"`
-		Review string `json:"review"`
+		Title    string `json:"title"`
+		Rating   int32  `json:"rating"`
+		Review   string `json:"review"`
+		Language string `json:"language"`
 	}{}
 	if err := c.Bind(f); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest).SetInternal(err)
@@ -206,11 +505,15 @@ func (h movieHandler) patchReview(c echo.Context) error {
 	review.Title = f.Title
 	review.Rating = f.Rating
 	review.Review = f.Review
+	if review.Language, err = getSearchConfig(f.Language, review.Language); err != nil {
+		return err
+	}
 
 	result, err := h.reviewStore.Update(ctx, review)
 	if err != nil {
 		return err
 	}
+	h.invalidateTaste(c, result.UserId)
 
 	return c.JSON(http.StatusOK, result)
 }
@@ -239,15 +542,179 @@ func (h movieHandler) deleteReview(c echo.Context) error {
 	if err := h.reviewStore.Delete(ctx, review.Id); err != nil {
 		return err
 	}
+	h.invalidateTaste(c, review.UserId)
 
 	return c.NoContent(http.StatusOK)
 }
 
-func (h movieHandler) searchMovies(c echo.Context) error {
-	movies, err := h.client.Search(c.Request().Context(), c.QueryParam("query"))
+// invalidateTaste is best effort, stale comparisons expire on their own.
+func (h movieHandler) invalidateTaste(c echo.Context, userId int32) {
+	if err := h.taste.Invalidate(c.Request().Context(), userId); err != nil {
+		c.Logger().Error("Invalidate: ", err)
+	}
+}
+
+const maxQueryLength = 200
+
+func (h movieHandler) search(c echo.Context) error {
+	p := search.Params{Query: strings.TrimSpace(c.QueryParam("query"))}
+	if p.Query == "" || utf8.RuneCountInString(p.Query) > maxQueryLength {
+		return echo.NewHTTPError(http.StatusBadRequest,
+			fmt.Sprintf("Query must be between 1 and %d characters", maxQueryLength))
+	}
+
+	var err error
+	if p.Page, err = queryInt32(c, "page", 1, 1, 500); err != nil {
+		return err
+	}
+	if p.Year, err = queryInt32(c, "year", 0, 1800, 2200); err != nil {
+		return err
+	}
+	if p.PrimaryReleaseYear, err = queryInt32(c, "primary_release_year", 0, 1800, 2200); err != nil {
+		return err
+	}
+	if p.IncludeAdult, err = queryBool(c, "include_adult"); err != nil {
+		return err
+	}
+
+	locale, err := getLocale(c)
 	if err != nil {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, movies)
+	ctx := c.Request().Context()
+	switch c.QueryParam("type") {
+	case "", search.MediaMovie:
+		page, err := h.client.SearchMovies(ctx, p, locale)
+		return respondPage(c, page, err)
+	case search.MediaPerson:
+		page, err := h.client.SearchPeople(ctx, p, locale)
+		return respondPage(c, page, err)
+	case search.MediaCollection:
+		page, err := h.client.SearchCollections(ctx, p, locale)
+		return respondPage(c, page, err)
+	case "multi":
+		page, err := h.client.SearchMulti(ctx, p, locale)
+		return respondPage(c, page, err)
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid type, must be movie, person, collection or multi")
+	}
+}
+
+func (h movieHandler) discover(c echo.Context) error {
+	var (
+		d   search.Discover
+		err error
+	)
+
+	if d.Page, err = queryInt32(c, "page", 1, 1, 500); err != nil {
+		return err
+	}
+	if d.RuntimeGTE, err = queryInt32(c, "runtime_gte", 0, 0, 1000); err != nil {
+		return err
+	}
+	if d.RuntimeLTE, err = queryInt32(c, "runtime_lte", 0, 0, 1000); err != nil {
+		return err
+	}
+	if d.RuntimeLTE != 0 && d.RuntimeGTE > d.RuntimeLTE {
+		return echo.NewHTTPError(http.StatusBadRequest, "runtime_gte must not be greater than runtime_lte")
+	}
+	if d.VoteCountGTE, err = queryInt32(c, "vote_count_gte", 0, 0, math.MaxInt32); err != nil {
+		return err
+	}
+	if d.VoteAverageGTE, err = queryFloat64(c, "vote_average_gte", 0, 0, 10); err != nil {
+		return err
+	}
+	if d.VoteAverageLTE, err = queryFloat64(c, "vote_average_lte", 0, 0, 10); err != nil {
+		return err
+	}
+	if d.VoteAverageLTE != 0 && d.VoteAverageGTE > d.VoteAverageLTE {
+		return echo.NewHTTPError(http.StatusBadRequest, "vote_average_gte must not be greater than vote_average_lte")
+	}
+	if d.IncludeAdult, err = queryBool(c, "include_adult"); err != nil {
+		return err
+	}
+
+	d.ReleaseDateGTE, d.ReleaseDateLTE = c.QueryParam("release_date_gte"), c.QueryParam("release_date_lte")
+	var from, to time.Time
+	if d.ReleaseDateGTE != "" {
+		if from, err = time.Parse(time.DateOnly, d.ReleaseDateGTE); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid release_date_gte, must be YYYY-MM-DD").SetInternal(err)
+		}
+	}
+	if d.ReleaseDateLTE != "" {
+		if to, err = time.Parse(time.DateOnly, d.ReleaseDateLTE); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid release_date_lte, must be YYYY-MM-DD").SetInternal(err)
+		}
+	}
+	if !from.IsZero() && !to.IsZero() && from.After(to) {
+		return echo.NewHTTPError(http.StatusBadRequest, "release_date_gte must not be after release_date_lte")
+	}
+
+	d.OriginalLanguage = c.QueryParam("original_language")
+	if d.OriginalLanguage != "" {
+		if base, err := language.ParseBase(d.OriginalLanguage); err != nil || base.String() != d.OriginalLanguage {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid original_language, must be an ISO 639-1 code")
+		}
+	}
+
+	d.SortBy = cmp.Or(c.QueryParam("sort_by"), "popularity.desc")
+	if !slices.Contains(search.DiscoverSortOptions, d.SortBy) {
+		return echo.NewHTTPError(http.StatusBadRequest,
+			"Invalid sort_by, must be one of "+strings.Join(search.DiscoverSortOptions, ", "))
+	}
+
+	locale, err := getLocale(c)
+	if err != nil {
+		return err
+	}
+
+	if genres := c.QueryParam("genres"); genres != "" {
+		known, err := h.client.GetGenres(c.Request().Context(), locale)
+		if err != nil {
+			return movieAPIError(c, err)
+		}
+
+		for idStr := range strings.SplitSeq(genres, ",") {
+			id, err := strconv.ParseInt(strings.TrimSpace(idStr), 10, 32)
+			if err != nil || !known.Contains(int32(id)) {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown genre %q", idStr))
+			}
+			d.Genres = append(d.Genres, int32(id))
+		}
+	}
+
+	page, err := h.client.Discover(c.Request().Context(), d, locale)
+	return respondPage(c, page, err)
+}
+
+func respondPage[T any](c echo.Context, page search.Page[T], err error) error {
+	if err != nil {
+		return movieAPIError(c, err)
+	}
+
+	if page.Results == nil {
+		page.Results = []T{}
+	}
+
+	return c.JSON(http.StatusOK, page)
+}
+
+// movieAPIError maps movieapi errors to the HTTP errors returned to clients.
+func movieAPIError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, movieapi.ErrNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "Not found").SetInternal(err)
+	case errors.Is(err, movieapi.ErrRateLimited):
+		var statusErr *movieapi.StatusError
+		if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+			retryAfter := int(math.Ceil(statusErr.RetryAfter.Seconds()))
+			c.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(retryAfter))
+		}
+		return echo.NewHTTPError(http.StatusServiceUnavailable, "Movie service is busy").SetInternal(err)
+	case errors.Is(err, movieapi.ErrUnavailable):
+		return echo.NewHTTPError(http.StatusBadGateway, "Movie service unavailable").SetInternal(err)
+	default:
+		return err
+	}
 }
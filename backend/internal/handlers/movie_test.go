@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/movie"
+)
+
+func TestBlendRankings(t *testing.T) {
+	ranking := func(ids ...int32) movie.Movies {
+		movies := make(movie.Movies, 0, len(ids))
+		for _, id := range ids {
+			movies = append(movies, movie.Movie{Id: id})
+		}
+		return movies
+	}
+
+	tests := []struct {
+		name     string
+		size     int
+		rankings []movie.Movies
+		want     []int32
+	}{
+		{
+			name:     "nothing to blend",
+			size:     20,
+			rankings: []movie.Movies{nil, nil},
+			want:     []int32{},
+		},
+		{
+			name:     "one ranking",
+			size:     20,
+			rankings: []movie.Movies{ranking(3, 1, 2), nil},
+			want:     []int32{3, 1, 2},
+		},
+		{
+			name:     "ranked in both first",
+			size:     20,
+			rankings: []movie.Movies{ranking(1, 2, 3), ranking(3, 1, 4)},
+			want:     []int32{1, 3, 2, 4},
+		},
+		{
+			name:     "ties keep the first ranking's order",
+			size:     20,
+			rankings: []movie.Movies{ranking(1, 2), ranking(3, 4)},
+			want:     []int32{1, 3, 2, 4},
+		},
+		{
+			name:     "limited to size",
+			size:     2,
+			rankings: []movie.Movies{ranking(1, 2, 3), ranking(3, 1, 4)},
+			want:     []int32{1, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]int32, 0)
+			for _, m := range blendRankings(tt.size, tt.rankings...) {
+				got = append(got, m.Id)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("blendRankings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlendRankingsKeepsFirstSeen(t *testing.T) {
+	local := movie.Movies{{Id: 1, Title: "local"}}
+	tmdb := movie.Movies{{Id: 1, Title: "tmdb"}}
+
+	if got := blendRankings(20, local, tmdb); len(got) != 1 || got[0].Title != "local" {
+		t.Errorf("blendRankings() = %v, want the local movie", got)
+	}
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/feed"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/notification"
+)
+
+type (
+	NotificationStore interface {
+		Read(ctx context.Context, userId int32, unreadOnly bool, cursor feed.Cursor, limit int32) ([]notification.Notification, error)
+		CountUnread(ctx context.Context, userId int32) (int64, error)
+		MarkRead(ctx context.Context, userId int32, ids []int32) (int64, error)
+		MarkAllRead(ctx context.Context, userId int32) (int64, error)
+	}
+
+	Notifier interface {
+		Notify(ctx context.Context, n notification.Notification) error
+	}
+
+	notificationHandler struct {
+		store NotificationStore
+	}
+
+	notificationPage struct {
+		feed.Page[notification.Notification]
+		UnreadCount int64 `json:"unread_count"`
+	}
+)
+
+func NewNotificationHandler(store NotificationStore) *notificationHandler {
+	return &notificationHandler{store}
+}
+
+func (h notificationHandler) RegisterRoutes(g *echo.Group, protection, csrf echo.MiddlewareFunc) {
+	g.GET("/me/notifications", h.getNotifications, protection)
+	g.POST("/me/notifications/read", h.markRead, protection, csrf)
+}
+
+func (h notificationHandler) getNotifications(c echo.Context) error {
+	cursor, limit, err := feedParams(c)
+	if err != nil {
+		return err
+	}
+
+	unreadOnly, err := queryBool(c, "unread")
+	if err != nil {
+		return err
+	}
+
+	ctx, userId := c.Request().Context(), MustGetUser(c).Id
+
+	notifications, err := h.store.Read(ctx, userId, unreadOnly, cursor, limit+1)
+	if err != nil {
+		return err
+	}
+
+	unread, err := h.store.CountUnread(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, notificationPage{
+		Page:        feed.NewPage(notifications, int(limit), notificationCursor),
+		UnreadCount: unread,
+	})
+}
+
+// markRead marks the notifications in ids as read, or all of them when all
+// is set.
+func (h notificationHandler) markRead(c echo.Context) error {
+	f := &struct {
+		Ids []int32 `json:"ids"`
+		All bool    `json:"all"`
+	}{}
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest).SetInternal(err)
+	}
+
+	if f.All == (len(f.Ids) > 0) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Either ids or all must be set")
+	}
+
+	ctx, userId := c.Request().Context(), MustGetUser(c).Id
+
+	var err error
+	if f.All {
+		_, err = h.store.MarkAllRead(ctx, userId)
+	} else {
+		_, err = h.store.MarkRead(ctx, userId, f.Ids)
+	}
+	if err != nil {
+		return err
+	}
+
+	unread, err := h.store.CountUnread(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{"unread_count": unread})
+}
+
+func notificationCursor(n notification.Notification) feed.Cursor {
+	return feed.Cursor{CreatedAt: n.CreatedAt, Id: n.Id}
+}
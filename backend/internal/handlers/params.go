@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/feed"
+)
+
+// queryInt32 parses an optional query param, which must be within [lo, hi].
+func queryInt32(c echo.Context, name string, fallback, lo, hi int32) (int32, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return fallback, nil
+	}
+
+	i, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || int32(i) < lo || int32(i) > hi {
+		return 0, echo.NewHTTPError(http.StatusBadRequest,
+			fmt.Sprintf("Invalid %s, must be between %d and %d", name, lo, hi)).SetInternal(err)
+	}
+
+	return int32(i), nil
+}
+
+// queryFloat64 is queryInt32 for decimal params.
+func queryFloat64(c echo.Context, name string, fallback, lo, hi float64) (float64, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return fallback, nil
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || !(f >= lo && f <= hi) {
+		return 0, echo.NewHTTPError(http.StatusBadRequest,
+			fmt.Sprintf("Invalid %s, must be between %g and %g", name, lo, hi)).SetInternal(err)
+	}
+
+	return f, nil
+}
+
+func queryBool(c echo.Context, name string) (bool, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid %s", name)).SetInternal(err)
+	}
+
+	return b, nil
+}
+
+// feedParams parses the cursor and limit of a keyset paginated list.
+func feedParams(c echo.Context) (feed.Cursor, int32, error) {
+	cursor, err := feed.ParseCursor(c.QueryParam("cursor"))
+	if err != nil {
+		return cursor, 0, echo.NewHTTPError(http.StatusBadRequest, "Not a valid cursor").SetInternal(err)
+	}
+
+	limit, err := queryInt32(c, "limit", 20, 1, 50)
+	return cursor, limit, err
+}
+
+// itemFeedParams is feedParams for feeds mixing several kinds of items.
+func itemFeedParams(c echo.Context) (feed.ItemCursor, int32, error) {
+	cursor, err := feed.ParseItemCursor(c.QueryParam("cursor"))
+	if err != nil {
+		return cursor, 0, echo.NewHTTPError(http.StatusBadRequest, "Not a valid cursor").SetInternal(err)
+	}
+
+	limit, err := queryInt32(c, "limit", 20, 1, 50)
+	return cursor, limit, err
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/movie"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/person"
+)
+
+type (
+	PersonClient interface {
+		GetPerson(ctx context.Context, id int32, locale movie.Locale) (person.Person, error)
+	}
+
+	personHandler struct {
+		client PersonClient
+	}
+)
+
+func NewPersonHandler(client PersonClient) *personHandler {
+	return &personHandler{client}
+}
+
+func (h personHandler) RegisterRoutes(g *echo.Group) {
+	g.GET("/:id", h.getPerson)
+}
+
+func (h personHandler) getPerson(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Not a valid person id").SetInternal(err)
+	}
+
+	locale, err := getLocale(c)
+	if err != nil {
+		return err
+	}
+
+	p, err := h.client.GetPerson(c.Request().Context(), int32(id), locale)
+	if err != nil {
+		return movieAPIError(c, err)
+	}
+
+	if p.Filmography != nil {
+		p.Filmography.SortByReleaseDate()
+	}
+
+	return c.JSON(http.StatusOK, p)
+}
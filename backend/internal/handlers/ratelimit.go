@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/config"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/user"
+)
+
+type (
+	RateLimiter interface {
+		Allow(ctx context.Context, key string, limit int, window time.Duration) (allowed bool, remaining int, reset time.Duration, err error)
+	}
+
+	// RateLimits holds one middleware per rate limited group of routes.
+	RateLimits struct {
+		Search  echo.MiddlewareFunc
+		Reviews echo.MiddlewareFunc
+		Auth    echo.MiddlewareFunc
+	}
+)
+
+func NewRateLimits(limiter RateLimiter, c config.RateLimits) RateLimits {
+	return RateLimits{
+		Search:  RateLimit(limiter, "search", c.Search),
+		Reviews: RateLimit(limiter, "reviews", c.Reviews),
+		Auth:    RateLimit(limiter, "auth", c.Auth),
+	}
+}
+
+// RateLimit limits requests per user when one is in the context, and per
+// client IP otherwise. Requests are let through if the limiter fails.
+func RateLimit(limiter RateLimiter, name string, policy config.RateLimit) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := fmt.Sprintf("%s:ip:%s", name, c.RealIP())
+			if u, ok := c.Get("user").(user.User); ok {
+				key = fmt.Sprintf("%s:user:%d", name, u.Id)
+			}
+
+			allowed, remaining, reset, err := limiter.Allow(c.Request().Context(), key, policy.Limit, policy.Window)
+			if err != nil {
+				c.Logger().Error("RateLimit: ", err)
+				return next(c)
+			}
+
+			resetSeconds := strconv.Itoa(int(math.Ceil(reset.Seconds())))
+			header := c.Response().Header()
+			header.Set("RateLimit-Limit", strconv.Itoa(policy.Limit))
+			header.Set("RateLimit-Remaining", strconv.Itoa(remaining))
+			header.Set("RateLimit-Reset", resetSeconds)
+
+			if !allowed {
+				header.Set(echo.HeaderRetryAfter, resetSeconds)
+				return echo.NewHTTPError(http.StatusTooManyRequests, "Too many requests")
+			}
+
+			return next(c)
+		}
+	}
+}
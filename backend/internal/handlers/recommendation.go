@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/movie"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/recommendation"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// favoriteRating is the lowest rating of a movie whose TMDB
+	// recommendations are used for users without enough local ones.
+	favoriteRating = 7
+	favoriteSeeds  = 3
+)
+
+type (
+	RecommendationStore interface {
+		Read(ctx context.Context, userId, limit int32) ([]recommendation.Recommendation, error)
+		ReadSeen(ctx context.Context, userId int32) ([]int32, error)
+		ReadFavorites(ctx context.Context, userId, minRating, limit int32) ([]int32, error)
+	}
+
+	RecommendationClient interface {
+		GetRecommendations(ctx context.Context, id, page int32, locale movie.Locale) (movie.Movies, error)
+		GetTrending(ctx context.Context, weekly bool, locale movie.Locale) (movie.Movies, error)
+	}
+
+	recommendationHandler struct {
+		store  RecommendationStore
+		client RecommendationClient
+		movies movieLookup
+	}
+)
+
+func NewRecommendationHandler(store RecommendationStore, client RecommendationClient, catalog MovieCatalog, catalogClient CatalogClient) *recommendationHandler {
+	return &recommendationHandler{store, client, newMovieLookup(catalog, catalogClient)}
+}
+
+func (h recommendationHandler) RegisterRoutes(g *echo.Group, protection echo.MiddlewareFunc) {
+	g.GET("/me/recommendations", h.getRecommendations, protection)
+}
+
+// getRecommendations returns the movies recommended from local ratings,
+// topped up from TMDB for users who don't have enough of them yet.
+func (h recommendationHandler) getRecommendations(c echo.Context) error {
+	limit, err := queryInt32(c, "limit", 20, 1, 50)
+	if err != nil {
+		return err
+	}
+
+	locale, err := getLocale(c)
+	if err != nil {
+		return err
+	}
+
+	ctx, userId := c.Request().Context(), MustGetUser(c).Id
+
+	recommendations, err := h.store.Read(ctx, userId, limit)
+	if err != nil {
+		return err
+	}
+
+	if err := h.attach(ctx, recommendations, locale); err != nil {
+		return err
+	}
+
+	if len(recommendations) < int(limit) {
+		fallback, err := h.coldStart(ctx, userId, recommendations, int(limit)-len(recommendations), locale)
+		if err != nil {
+			c.Logger().Error("recommendations: ", err)
+		}
+		recommendations = append(recommendations, fallback...)
+	}
+
+	return c.JSON(http.StatusOK, recommendations)
+}
+
+func (h recommendationHandler) attach(ctx context.Context, recommendations []recommendation.Recommendation, locale movie.Locale) error {
+	ids := make([]int32, 0, len(recommendations))
+	for _, r := range recommendations {
+		ids = append(ids, r.MovieId)
+	}
+
+	movies, err := h.movies.findIn(ctx, ids, locale)
+	if err != nil {
+		return err
+	}
+
+	for i, r := range recommendations {
+		if m, ok := movies[r.MovieId]; ok {
+			recommendations[i].Movie = &m
+		}
+	}
+
+	return nil
+}
+
+// coldStart returns up to n TMDB recommendations for the movies the user
+// rated best, or the week's trending movies when they have none, leaving
+// out the movies they saw and the ones already recommended.
+func (h recommendationHandler) coldStart(ctx context.Context, userId int32, recommended []recommendation.Recommendation, n int, locale movie.Locale) ([]recommendation.Recommendation, error) {
+	seen, err := h.store.ReadSeen(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	skip := make(map[int32]bool, len(seen)+len(recommended))
+	for _, id := range seen {
+		skip[id] = true
+	}
+	for _, r := range recommended {
+		skip[r.MovieId] = true
+	}
+
+	seeds, err := h.store.ReadFavorites(ctx, userId, favoriteRating, favoriteSeeds)
+	if err != nil {
+		return nil, err
+	}
+
+	lists := make([]movie.Movies, max(len(seeds), 1))
+	if len(seeds) == 0 {
+		if lists[0], err = h.client.GetTrending(ctx, true, locale); err != nil {
+			return nil, err
+		}
+	} else {
+		var g errgroup.Group
+		for i, seed := range seeds {
+			g.Go(func() (err error) {
+				lists[i], err = h.client.GetRecommendations(ctx, seed, 1, locale)
+				return err
+			})
+		}
+		if err := g.Wait(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Take from every list in turn, so each seed gets its share.
+	fallback := make([]recommendation.Recommendation, 0, n)
+	for i := 0; len(fallback) < n; i++ {
+		exhausted := true
+		for _, list := range lists {
+			if i >= len(list) {
+				continue
+			}
+			exhausted = false
+
+			m := list[i]
+			if skip[m.Id] || len(fallback) == n {
+				continue
+			}
+			skip[m.Id] = true
+
+			fallback = append(fallback, recommendation.Recommendation{
+				MovieId: m.Id, Source: recommendation.SourceTMDB, Movie: &m,
+			})
+		}
+
+		if exhausted {
+			break
+		}
+	}
+
+	return fallback, nil
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/feed"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/movie"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/search"
+)
+
+type (
+	ReviewReader interface {
+		Search(ctx context.Context, p search.ReviewParams) (search.Page[search.ReviewHit], error)
+		ReadRecent(ctx context.Context, cursor feed.Cursor, limit int32) (movie.Reviews, error)
+	}
+
+	reviewHandler struct {
+		reviews ReviewReader
+		movies  movieLookup
+	}
+)
+
+func NewReviewHandler(reviews ReviewReader, catalog MovieCatalog, client CatalogClient) *reviewHandler {
+	return &reviewHandler{reviews, newMovieLookup(catalog, client)}
+}
+
+func (h reviewHandler) RegisterRoutes(g *echo.Group, limits RateLimits) {
+	g.GET("/search", h.search, limits.Search)
+	g.GET("/recent", h.getRecent)
+}
+
+func (h reviewHandler) getRecent(c echo.Context) error {
+	cursor, limit, err := feedParams(c)
+	if err != nil {
+		return err
+	}
+
+	reviews, err := h.reviews.ReadRecent(c.Request().Context(), cursor, limit+1)
+	if err != nil {
+		return err
+	}
+
+	page := feed.NewPage(reviews, int(limit), reviewCursor)
+	if err := h.movies.attach(c.Request().Context(), page.Results); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, page)
+}
+
+func reviewCursor(r movie.Review) feed.Cursor {
+	return feed.Cursor{CreatedAt: r.CreatedAt, Id: r.Id}
+}
+
+func (h reviewHandler) search(c echo.Context) error {
+	p := search.ReviewParams{Query: strings.TrimSpace(c.QueryParam("q"))}
+	if p.Query == "" || utf8.RuneCountInString(p.Query) > maxQueryLength {
+		return echo.NewHTTPError(http.StatusBadRequest,
+			fmt.Sprintf("Query must be between 1 and %d characters", maxQueryLength))
+	}
+
+	var err error
+	if p.Page, err = queryInt32(c, "page", 1, 1, 500); err != nil {
+		return err
+	}
+	if p.MovieId, err = queryInt32(c, "movie_id", 0, 1, math.MaxInt32); err != nil {
+		return err
+	}
+	if p.UserId, err = queryInt32(c, "user_id", 0, 1, math.MaxInt32); err != nil {
+		return err
+	}
+	if p.MinRating, err = queryInt32(c, "min_rating", 0, 0, 10); err != nil {
+		return err
+	}
+	if p.MaxRating, err = queryInt32(c, "max_rating", 10, 0, 10); err != nil {
+		return err
+	}
+	if p.MinRating > p.MaxRating {
+		return echo.NewHTTPError(http.StatusBadRequest, "min_rating can't be greater than max_rating")
+	}
+
+	page, err := h.reviews.Search(c.Request().Context(), p)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, page)
+}
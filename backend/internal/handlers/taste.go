@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/config"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/user"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/stores"
+)
+
+type (
+	TasteStore interface {
+		ReadCompatibility(ctx context.Context, userId int32, other user.User, minOverlap int32) (user.Compatibility, error)
+		ReadSimilar(ctx context.Context, userId, minOverlap, limit int32) ([]user.Compatibility, error)
+	}
+
+	// TasteCache returns the version a missed comparison is saved under
+	// along with the miss.
+	TasteCache interface {
+		ReadCompatibility(ctx context.Context, userId, otherId int32) (user.Compatibility, stores.TasteVersion, error)
+		SaveCompatibility(ctx context.Context, version stores.TasteVersion, compatibility user.Compatibility) error
+		ReadSimilar(ctx context.Context, userId int32) ([]user.Compatibility, stores.TasteVersion, error)
+		SaveSimilar(ctx context.Context, version stores.TasteVersion, similar []user.Compatibility) error
+	}
+
+	tasteHandler struct {
+		store  TasteStore
+		cache  TasteCache
+		users  UserFinder
+		config config.Taste
+	}
+)
+
+func NewTasteHandler(store TasteStore, cache TasteCache, users UserFinder, c config.Taste) *tasteHandler {
+	return &tasteHandler{store, cache, users, c}
+}
+
+func (h tasteHandler) RegisterRoutes(g *echo.Group, protection echo.MiddlewareFunc) {
+	g.GET("/me/similar", h.getSimilar, protection)
+	g.GET("/:username/compatibility", h.getCompatibility, protection)
+}
+
+// getCompatibility compares the user's taste to the one of the user in the
+// path. The score is null until they reviewed enough movies in common.
+func (h tasteHandler) getCompatibility(c echo.Context) error {
+	ctx, me := c.Request().Context(), MustGetUser(c)
+
+	other, err := h.users.ReadByUsername(ctx, c.Param("username"))
+	if err != nil {
+		if errors.Is(err, stores.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "user not found").SetInternal(err)
+		}
+		return err
+	}
+
+	if other.Id == me.Id {
+		return echo.NewHTTPError(http.StatusBadRequest, "You can't compare yourself to yourself")
+	}
+
+	compatibility, version, err := h.cache.ReadCompatibility(ctx, me.Id, other.Id)
+	if err == nil {
+		compatibility.User = other
+		return c.JSON(http.StatusOK, compatibility)
+	}
+	if !errors.Is(err, stores.ErrNotFound) {
+		c.Logger().Error("ReadCompatibility: ", err)
+	}
+
+	compatibility, err = h.store.ReadCompatibility(ctx, me.Id, other, int32(h.config.MinOverlap))
+	if err != nil {
+		return err
+	}
+
+	if err := h.cache.SaveCompatibility(ctx, version, compatibility); err != nil {
+		c.Logger().Error("SaveCompatibility: ", err)
+	}
+
+	return c.JSON(http.StatusOK, compatibility)
+}
+
+// getSimilar lists the users whose taste is most alike the user's.
+func (h tasteHandler) getSimilar(c echo.Context) error {
+	ctx, userId := c.Request().Context(), MustGetUser(c).Id
+
+	similar, version, err := h.cache.ReadSimilar(ctx, userId)
+	if err == nil {
+		return c.JSON(http.StatusOK, similar)
+	}
+	if !errors.Is(err, stores.ErrNotFound) {
+		c.Logger().Error("ReadSimilar: ", err)
+	}
+
+	similar, err = h.store.ReadSimilar(ctx, userId, int32(h.config.MinOverlap), int32(h.config.SimilarLimit))
+	if err != nil {
+		return err
+	}
+
+	if err := h.cache.SaveSimilar(ctx, version, similar); err != nil {
+		c.Logger().Error("SaveSimilar: ", err)
+	}
+
+	return c.JSON(http.StatusOK, similar)
+}
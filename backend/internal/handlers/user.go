@@ -15,6 +15,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/markbates/goth/gothic"
 	"github.com/rodrigoaraujo46/flickmeter/backend/internal/config"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/cookies"
 	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/refresh"
 	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/session"
 	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/user"
@@ -42,27 +43,30 @@ type (
 		sessionStore SessionStore
 		refreshStore RefreshStore
 		userStore    UserStore
+		cookies      cookies.Policy
 	}
 )
 
-func NewUserHandler(authStore SessionStore, refreshStore RefreshStore, userStore UserStore, gothicConfig config.Gothic) userHandler {
-	oauth.StartOAuth(gothicConfig)
+func NewUserHandler(authStore SessionStore, refreshStore RefreshStore, userStore UserStore, gothicConfig config.Gothic, cookiePolicy cookies.Policy) userHandler {
+	oauth.StartOAuth(gothicConfig, cookiePolicy)
 	return userHandler{
 		sessionStore: authStore,
 		refreshStore: refreshStore,
 		userStore:    userStore,
+		cookies:      cookiePolicy,
 	}
 }
 
-func (h userHandler) RegisterRoutes(g *echo.Group, protection echo.MiddlewareFunc) {
-	g.GET("/auth/:provider", h.getProvider)
-	g.GET("/auth/:provider/callback", h.getCallback)
+func (h userHandler) RegisterRoutes(g *echo.Group, protection, csrf echo.MiddlewareFunc, limits RateLimits) {
+	g.GET("/auth/:provider", h.getProvider, limits.Auth)
+	g.GET("/auth/:provider/callback", h.getCallback, limits.Auth)
+	g.GET("/csrf", getCSRFToken, csrf)
 	g.GET("/me", h.getMe, protection)
-	g.POST("/logout", h.logout, protection)
+	g.POST("/logout", h.logout, protection, csrf)
 }
 
 func (h userHandler) getUserFromSession(c echo.Context) (u user.User, err error) {
-	cookie, err := c.Cookie("session")
+	cookie, err := c.Cookie(h.cookies.Name(session.CookieName))
 	if err := cmp.Or(err, cookie.Valid()); err != nil {
 		return u, err
 	}
@@ -76,7 +80,7 @@ func (h userHandler) getUserFromSession(c echo.Context) (u user.User, err error)
 }
 
 func (h userHandler) getUserFromRefresh(c echo.Context) (u user.User, err error) {
-	cookie, err := c.Cookie("refresh")
+	cookie, err := c.Cookie(h.cookies.Name(refresh.CookieName))
 	if err := cmp.Or(err, cookie.Valid()); err != nil {
 		return u, err
 	}
@@ -110,7 +114,7 @@ func (h userHandler) Authentication(next echo.HandlerFunc) echo.HandlerFunc {
 
 		ses := session.New(uuid.NewString(), u)
 		if h.sessionStore.Create(c.Request().Context(), *ses) != nil {
-			c.SetCookie(ses.Cookie())
+			c.SetCookie(ses.Cookie(h.cookies))
 		}
 
 		return next(c)
@@ -176,7 +180,7 @@ func (h userHandler) getCallback(c echo.Context) error {
 	if err := h.sessionStore.Create(c.Request().Context(), ses); err != nil {
 		return c.Redirect(http.StatusSeeOther, redirectURL)
 	}
-	c.SetCookie(ses.Cookie())
+	c.SetCookie(ses.Cookie(h.cookies))
 
 	keep, err := strconv.ParseBool(values.Get("keep"))
 	if err != nil {
@@ -187,7 +191,7 @@ func (h userHandler) getCallback(c echo.Context) error {
 	if err := h.refreshStore.Create(c.Request().Context(), ref); err != nil {
 		return c.Redirect(http.StatusSeeOther, redirectURL)
 	}
-	c.SetCookie(ref.Cookie())
+	c.SetCookie(ref.Cookie(h.cookies))
 
 	return c.Redirect(http.StatusSeeOther, redirectURL)
 }
@@ -199,7 +203,7 @@ func (h userHandler) getMe(c echo.Context) error {
 func (h userHandler) logout(c echo.Context) error {
 	var errs error
 
-	if refreshCookie, err := c.Cookie("refresh"); err == nil {
+	if refreshCookie, err := c.Cookie(h.cookies.Name(refresh.CookieName)); err == nil {
 		if id, err := uuid.Parse(refreshCookie.Value); err != nil {
 			c.Echo().Logger.Error("Invalid refresh token", err)
 			errs = errors.Join(errs, err)
@@ -207,32 +211,16 @@ func (h userHandler) logout(c echo.Context) error {
 			c.Echo().Logger.Error("Failed to delete refresh token", err)
 			errs = errors.Join(errs, err)
 		} else {
-			c.SetCookie(&http.Cookie{
-				Name:     "refresh",
-				Value:    "",
-				Path:     "/",
-				MaxAge:   -1,
-				HttpOnly: true,
-				Secure:   true,
-				SameSite: http.SameSiteLaxMode,
-			})
+			c.SetCookie(h.cookies.Expire(refresh.CookieName))
 		}
 	}
 
-	if sessionCookie, err := c.Cookie("session"); err == nil {
+	if sessionCookie, err := c.Cookie(h.cookies.Name(session.CookieName)); err == nil {
 		if err := h.sessionStore.Delete(c.Request().Context(), sessionCookie.Value); err != nil {
 			c.Echo().Logger.Error("Failed to delete session token", err)
 			errs = errors.Join(errs, err)
 		} else {
-			c.SetCookie(&http.Cookie{
-				Name:     "session",
-				Value:    "",
-				Path:     "/",
-				MaxAge:   -1,
-				HttpOnly: true,
-				Secure:   true,
-				SameSite: http.SameSiteLaxMode,
-			})
+			c.SetCookie(h.cookies.Expire(session.CookieName))
 		}
 	}
 
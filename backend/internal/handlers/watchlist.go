@@ -1,9 +1,16 @@
 package handlers
 
-import "github.com/labstack/echo/v4"
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
 
 type (
-	WatchlistStore interface{}
+	WatchlistStore interface {
+		Add(ctx context.Context, userId, movieId int32) (bool, error)
+	}
 
 	watchlistHandler struct {
 		watchlistStore WatchlistStore
@@ -14,5 +21,31 @@ func NewWatchlistHandler(watchlistStore WatchlistStore) *watchlistHandler {
 	return &watchlistHandler{watchlistStore}
 }
 
-func (w *watchlistHandler) RegisterRoutes(group *echo.Group) {
+func (w *watchlistHandler) RegisterRoutes(group *echo.Group, protection, csrf echo.MiddlewareFunc) {
+	group.POST("", w.postWatchlist, protection, csrf)
+}
+
+// postWatchlist adds a movie to the user's watchlist, answering 201 when it
+// wasn't on it yet and 200 when it was.
+func (w *watchlistHandler) postWatchlist(c echo.Context) error {
+	f := &struct {
+		MovieId int32 `json:"movie_id"`
+	}{}
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest).SetInternal(err)
+	}
+
+	if f.MovieId <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Not a valid movie id")
+	}
+
+	added, err := w.watchlistStore.Add(c.Request().Context(), MustGetUser(c).Id, f.MovieId)
+	if err != nil {
+		return err
+	}
+
+	if !added {
+		return c.NoContent(http.StatusOK)
+	}
+	return c.NoContent(http.StatusCreated)
 }
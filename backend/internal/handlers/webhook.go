@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/webhook"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/stores"
+)
+
+const deliveriesLimit = 100
+
+type (
+	WebhookStore interface {
+		Create(ctx context.Context, w webhook.Webhook) (webhook.Webhook, error)
+		List(ctx context.Context) ([]webhook.Webhook, error)
+		Delete(ctx context.Context, id int32) error
+		ReadDeliveries(ctx context.Context, webhookId int32, status string, limit int32) ([]webhook.Delivery, error)
+		Retry(ctx context.Context, webhookId, id int32) error
+	}
+
+	webhookHandler struct {
+		store WebhookStore
+	}
+)
+
+func NewWebhookHandler(store WebhookStore) *webhookHandler {
+	return &webhookHandler{store}
+}
+
+// RegisterRoutes registers the webhook routes, all of them for admins only.
+func (h webhookHandler) RegisterRoutes(g *echo.Group, protection, csrf, admin echo.MiddlewareFunc) {
+	g.Use(protection, admin)
+
+	g.GET("", h.getWebhooks)
+	g.POST("", h.postWebhook, csrf)
+	g.DELETE("/:id", h.deleteWebhook, csrf)
+	g.GET("/:id/deliveries", h.getDeliveries)
+	g.POST("/:id/deliveries/:deliveryId/retry", h.retryDelivery, csrf)
+}
+
+func (h webhookHandler) getWebhooks(c echo.Context) error {
+	webhooks, err := h.store.List(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	return c.JSON(http.StatusOK, webhooks)
+}
+
+// postWebhook subscribes a URL to events. The secret is generated when
+// missing, and only returned here.
+func (h webhookHandler) postWebhook(c echo.Context) error {
+	f := &struct {
+		URL    string   `json:"url"`
+		Secret string   `json:"secret"`
+		Events []string `json:"events"`
+	}{}
+	if err := c.Bind(f); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest).SetInternal(err)
+	}
+
+	if u, err := url.Parse(f.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Not a valid http(s) url")
+	}
+
+	if len(f.Events) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "At least one event is required")
+	}
+	for _, event := range f.Events {
+		if !slices.Contains(webhook.Events, event) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Unknown event "+strconv.Quote(event))
+		}
+	}
+	slices.Sort(f.Events)
+
+	if f.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return err
+		}
+		f.Secret = hex.EncodeToString(secret)
+	}
+
+	created, err := h.store.Create(c.Request().Context(), webhook.Webhook{
+		URL:    f.URL,
+		Secret: f.Secret,
+		Events: slices.Compact(f.Events),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, created)
+}
+
+func (h webhookHandler) deleteWebhook(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Not a valid webhook id").SetInternal(err)
+	}
+
+	if err := h.store.Delete(c.Request().Context(), int32(id)); err != nil {
+		if errors.Is(err, stores.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "webhook not found").SetInternal(err)
+		}
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// getDeliveries lists the latest deliveries of a webhook with the status
+// query param, dead ones by default.
+func (h webhookHandler) getDeliveries(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Not a valid webhook id").SetInternal(err)
+	}
+
+	status := c.QueryParam("status")
+	switch status {
+	case "":
+		status = webhook.StatusDead
+	case webhook.StatusPending, webhook.StatusDelivered, webhook.StatusDead:
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "Not a valid delivery status")
+	}
+
+	deliveries, err := h.store.ReadDeliveries(c.Request().Context(), int32(id), status, deliveriesLimit)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, deliveries)
+}
+
+// retryDelivery queues a dead delivery again.
+func (h webhookHandler) retryDelivery(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Not a valid webhook id").SetInternal(err)
+	}
+
+	deliveryId, err := strconv.ParseInt(c.Param("deliveryId"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Not a valid delivery id").SetInternal(err)
+	}
+
+	if err := h.store.Retry(c.Request().Context(), int32(id), int32(deliveryId)); err != nil {
+		if errors.Is(err, stores.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "dead delivery not found").SetInternal(err)
+		}
+		return err
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
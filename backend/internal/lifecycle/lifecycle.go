@@ -0,0 +1,126 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type (
+	Logger interface {
+		Errorf(format string, args ...any)
+	}
+
+	// Worker is a background task. Run must return once ctx is cancelled.
+	Worker interface {
+		Run(ctx context.Context) error
+	}
+
+	WorkerFunc func(ctx context.Context) error
+
+	namedWorker struct {
+		name   string
+		worker Worker
+	}
+
+	namedCloser struct {
+		name  string
+		close func() error
+	}
+
+	// Lifecycle starts background workers and stops them, together with the
+	// resources they depend on, in an orderly fashion.
+	Lifecycle struct {
+		logger  Logger
+		workers []namedWorker
+		closers []namedCloser
+
+		wg     sync.WaitGroup
+		cancel context.CancelFunc
+	}
+)
+
+func (f WorkerFunc) Run(ctx context.Context) error {
+	return f(ctx)
+}
+
+func New(logger Logger) *Lifecycle {
+	return &Lifecycle{logger: logger}
+}
+
+// Go registers a worker to be run on Start.
+func (l *Lifecycle) Go(name string, w Worker) {
+	l.workers = append(l.workers, namedWorker{name, w})
+}
+
+// OnStop registers a closer to be called on Stop, after every worker has
+// returned. Closers are called in reverse registration order.
+func (l *Lifecycle) OnStop(name string, close func() error) {
+	l.closers = append(l.closers, namedCloser{name, close})
+}
+
+func (l *Lifecycle) Start(c context.Context) {
+	ctx, cancel := context.WithCancel(c)
+	l.cancel = cancel
+
+	for _, w := range l.workers {
+		l.wg.Go(func() {
+			if err := w.worker.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				l.logger.Errorf("lifecycle: worker %s stopped: %v", w.name, err)
+			}
+		})
+	}
+}
+
+// Stop cancels every worker and waits for them to return, or for ctx to be
+// done, before closing the registered resources.
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	var errs error
+
+	if l.cancel != nil {
+		l.cancel()
+
+		done := make(chan struct{})
+		go func() {
+			l.wg.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			errs = errors.Join(errs, errors.New("lifecycle: timed out waiting for workers"))
+		}
+	}
+
+	for i := len(l.closers) - 1; i >= 0; i-- {
+		if err := l.closers[i].close(); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("lifecycle: closing %s: %w", l.closers[i].name, err))
+		}
+	}
+
+	return errs
+}
+
+// Every registers a worker calling fn right away and then once per interval.
+// Errors returned by fn are logged and don't stop the worker.
+func (l *Lifecycle) Every(name string, interval time.Duration, fn func(ctx context.Context) error) {
+	l.Go(name, WorkerFunc(func(ctx context.Context) error {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := fn(ctx); err != nil && ctx.Err() == nil {
+				l.logger.Errorf("lifecycle: %s: %v", name, err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-ticker.C:
+			}
+		}
+	}))
+}
@@ -0,0 +1,71 @@
+package feed
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks the last item of a page ordered newest first, the next page
+// starts right after it.
+type Cursor struct {
+	CreatedAt time.Time
+	Id        int32
+}
+
+// Start is the cursor of the first page.
+func Start() Cursor {
+	return Cursor{CreatedAt: time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC), Id: math.MaxInt32}
+}
+
+// ParseCursor decodes a cursor returned by String, an empty s is the first
+// page.
+func ParseCursor(s string) (Cursor, error) {
+	if s == "" {
+		return Start(), nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	var (
+		nanos int64
+		c     Cursor
+	)
+	if _, err := fmt.Sscanf(string(b), "%d:%d", &nanos, &c.Id); err != nil {
+		return Cursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	c.CreatedAt = time.Unix(0, nanos)
+
+	return c, nil
+}
+
+func (c Cursor) String() string {
+	return base64.RawURLEncoding.EncodeToString(fmt.Appendf(nil, "%d:%d", c.CreatedAt.UnixNano(), c.Id))
+}
+
+type Page[T any] struct {
+	Results    []T    `json:"results"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// NewPage returns a page of at most limit items out of items, which should
+// hold up to limit+1 so the existence of a next page is known.
+func NewPage[T any, C fmt.Stringer](items []T, limit int, cursor func(T) C) Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+
+	if len(items) <= limit {
+		return Page[T]{Results: items}
+	}
+
+	items = items[:limit]
+	return Page[T]{Results: items, NextCursor: cursor(items[limit-1]).String()}
+}
@@ -0,0 +1,103 @@
+package feed
+
+import (
+	"encoding/base64"
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{name: "epoch", cursor: Cursor{CreatedAt: time.Unix(0, 0), Id: 0}},
+		{name: "nanoseconds", cursor: Cursor{CreatedAt: time.Date(2025, 3, 14, 15, 9, 26, 535897932, time.UTC), Id: 42}},
+		{name: "largest id", cursor: Cursor{CreatedAt: time.Date(2262, 1, 1, 0, 0, 0, 0, time.UTC), Id: math.MaxInt32}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCursor(tt.cursor.String())
+			if err != nil {
+				t.Fatalf("ParseCursor() error = %v", err)
+			}
+			if !got.CreatedAt.Equal(tt.cursor.CreatedAt) || got.Id != tt.cursor.Id {
+				t.Errorf("ParseCursor() = %v, want %v", got, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestParseCursor(t *testing.T) {
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+
+	tests := []struct {
+		name    string
+		s       string
+		want    Cursor
+		wantErr bool
+	}{
+		{name: "first page", s: "", want: Start()},
+		{name: "decoded", s: encode("1000:7"), want: Cursor{CreatedAt: time.Unix(0, 1000), Id: 7}},
+		{name: "not base64", s: "not a cursor!", wantErr: true},
+		{name: "padded base64", s: base64.URLEncoding.EncodeToString([]byte("10000:7")), wantErr: true},
+		{name: "missing id", s: encode("1000"), wantErr: true},
+		{name: "not numbers", s: encode("yesterday:7"), wantErr: true},
+		{name: "id overflow", s: encode("1000:" + strconv.Itoa(math.MaxInt32+1)), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCursor(tt.s)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidCursor) {
+					t.Errorf("ParseCursor() error = %v, want ErrInvalidCursor", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseCursor() error = %v", err)
+			}
+			if !got.CreatedAt.Equal(tt.want.CreatedAt) || got.Id != tt.want.Id {
+				t.Errorf("ParseCursor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPage(t *testing.T) {
+	cursor := func(id int32) Cursor {
+		return Cursor{CreatedAt: time.Unix(int64(id), 0), Id: id}
+	}
+
+	tests := []struct {
+		name       string
+		items      []int32
+		limit      int
+		wantLen    int
+		wantCursor string
+	}{
+		{name: "no items", items: nil, limit: 2, wantLen: 0},
+		{name: "last page", items: []int32{3, 2}, limit: 2, wantLen: 2},
+		{name: "more to come", items: []int32{3, 2, 1}, limit: 2, wantLen: 2, wantCursor: cursor(2).String()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := NewPage(tt.items, tt.limit, cursor)
+			if page.Results == nil || len(page.Results) != tt.wantLen {
+				t.Errorf("NewPage() results = %v, want %d", page.Results, tt.wantLen)
+			}
+			if page.NextCursor != tt.wantCursor {
+				t.Errorf("NewPage() next cursor = %q, want %q", page.NextCursor, tt.wantCursor)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package feed
+
+import (
+	"cmp"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/movie"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/user"
+)
+
+const (
+	TypeReview    = "review"
+	TypeWatchlist = "watchlist"
+)
+
+// Kinds order items created at the same time, they must match the ones the
+// feed queries compare to.
+const (
+	KindWatchlist int32 = 1
+	KindReview    int32 = 2
+)
+
+// Item is something a user did, only the field named by Type is set.
+type Item struct {
+	Type      string        `json:"type"`
+	CreatedAt time.Time     `json:"created_at"`
+	User      user.User     `json:"user"`
+	MovieId   int32         `json:"movie_id"`
+	Movie     *movie.Movie  `json:"movie,omitempty"`
+	Review    *movie.Review `json:"review,omitempty"`
+}
+
+func NewReviewItem(r movie.Review) Item {
+	return Item{Type: TypeReview, CreatedAt: r.CreatedAt, User: r.User, MovieId: r.MovieId, Review: &r}
+}
+
+func NewWatchlistItem(u user.User, movieId int32, createdAt time.Time) Item {
+	return Item{Type: TypeWatchlist, CreatedAt: createdAt, User: u, MovieId: movieId}
+}
+
+// ItemCursor marks the last item of a feed page. Items are ordered newest
+// first, then by kind, user and id, the review id for reviews and the movie
+// id for watchlist additions, which together are unique.
+type ItemCursor struct {
+	CreatedAt time.Time
+	Kind      int32
+	UserId    int32
+	Id        int32
+}
+
+// StartItems is the cursor of the first feed page.
+func StartItems() ItemCursor {
+	return ItemCursor{CreatedAt: Start().CreatedAt, Kind: math.MaxInt32, UserId: math.MaxInt32, Id: math.MaxInt32}
+}
+
+// ParseItemCursor decodes a cursor returned by ItemCursor.String, an empty
+// s is the first page.
+func ParseItemCursor(s string) (ItemCursor, error) {
+	if s == "" {
+		return StartItems(), nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return ItemCursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	var (
+		nanos int64
+		c     ItemCursor
+	)
+	if _, err := fmt.Sscanf(string(b), "%d:%d:%d:%d", &nanos, &c.Kind, &c.UserId, &c.Id); err != nil {
+		return ItemCursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	c.CreatedAt = time.Unix(0, nanos)
+
+	return c, nil
+}
+
+func (c ItemCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString(
+		fmt.Appendf(nil, "%d:%d:%d:%d", c.CreatedAt.UnixNano(), c.Kind, c.UserId, c.Id))
+}
+
+// Compare orders cursors the way the feed is, newest first.
+func (c ItemCursor) Compare(other ItemCursor) int {
+	return cmp.Or(
+		other.CreatedAt.Compare(c.CreatedAt),
+		cmp.Compare(other.Kind, c.Kind),
+		cmp.Compare(other.UserId, c.UserId),
+		cmp.Compare(other.Id, c.Id),
+	)
+}
+
+func (i Item) Cursor() ItemCursor {
+	if i.Review != nil {
+		return ItemCursor{CreatedAt: i.CreatedAt, Kind: KindReview, UserId: i.User.Id, Id: i.Review.Id}
+	}
+	return ItemCursor{CreatedAt: i.CreatedAt, Kind: KindWatchlist, UserId: i.User.Id, Id: i.MovieId}
+}
+
+// SortItems sorts items newest first, in cursor order.
+func SortItems(items []Item) {
+	slices.SortFunc(items, func(a, b Item) int {
+		return a.Cursor().Compare(b.Cursor())
+	})
+}
@@ -0,0 +1,130 @@
+package feed
+
+import (
+	"encoding/base64"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/movie"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/user"
+)
+
+func TestItemCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor ItemCursor
+	}{
+		{name: "review", cursor: ItemCursor{CreatedAt: time.Unix(1700000000, 123), Kind: KindReview, UserId: 3, Id: 9}},
+		{name: "watchlist", cursor: ItemCursor{CreatedAt: time.Unix(1700000000, 123), Kind: KindWatchlist, UserId: 3, Id: 550}},
+		{name: "zero", cursor: ItemCursor{CreatedAt: time.Unix(0, 0)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseItemCursor(tt.cursor.String())
+			if err != nil {
+				t.Fatalf("ParseItemCursor() error = %v", err)
+			}
+			if got.Compare(tt.cursor) != 0 {
+				t.Errorf("ParseItemCursor() = %v, want %v", got, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestParseItemCursor(t *testing.T) {
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+
+	tests := []struct {
+		name    string
+		s       string
+		want    ItemCursor
+		wantErr bool
+	}{
+		{name: "first page", s: "", want: StartItems()},
+		{name: "decoded", s: encode("1000:2:3:4"), want: ItemCursor{CreatedAt: time.Unix(0, 1000), Kind: 2, UserId: 3, Id: 4}},
+		{name: "not base64", s: "not a cursor!", wantErr: true},
+		{name: "feed cursor", s: encode("1000:4"), wantErr: true},
+		{name: "not numbers", s: encode("1000:review:3:4"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseItemCursor(tt.s)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidCursor) {
+					t.Errorf("ParseItemCursor() error = %v, want ErrInvalidCursor", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseItemCursor() error = %v", err)
+			}
+			if got.Compare(tt.want) != 0 {
+				t.Errorf("ParseItemCursor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortItems(t *testing.T) {
+	at := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	review := func(id, userId int32, createdAt time.Time) Item {
+		return NewReviewItem(movie.Review{Id: id, CreatedAt: createdAt, User: user.User{Id: userId}})
+	}
+	watchlist := func(movieId, userId int32, createdAt time.Time) Item {
+		return NewWatchlistItem(user.User{Id: userId}, movieId, createdAt)
+	}
+
+	tests := []struct {
+		name  string
+		items []Item
+		want  []ItemCursor
+	}{
+		{
+			name:  "newest first",
+			items: []Item{review(1, 1, at.Add(-time.Hour)), watchlist(5, 1, at), review(2, 1, at.Add(time.Hour))},
+			want: []ItemCursor{
+				{CreatedAt: at.Add(time.Hour), Kind: KindReview, UserId: 1, Id: 2},
+				{CreatedAt: at, Kind: KindWatchlist, UserId: 1, Id: 5},
+				{CreatedAt: at.Add(-time.Hour), Kind: KindReview, UserId: 1, Id: 1},
+			},
+		},
+		{
+			name:  "same time, reviews first",
+			items: []Item{watchlist(5, 1, at), review(1, 1, at)},
+			want: []ItemCursor{
+				{CreatedAt: at, Kind: KindReview, UserId: 1, Id: 1},
+				{CreatedAt: at, Kind: KindWatchlist, UserId: 1, Id: 5},
+			},
+		},
+		{
+			name:  "same time and kind, by user then id",
+			items: []Item{watchlist(5, 1, at), watchlist(5, 2, at), watchlist(7, 1, at)},
+			want: []ItemCursor{
+				{CreatedAt: at, Kind: KindWatchlist, UserId: 2, Id: 5},
+				{CreatedAt: at, Kind: KindWatchlist, UserId: 1, Id: 7},
+				{CreatedAt: at, Kind: KindWatchlist, UserId: 1, Id: 5},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SortItems(tt.items)
+
+			got := make([]ItemCursor, 0, len(tt.items))
+			for _, item := range tt.items {
+				got = append(got, item.Cursor())
+			}
+			if !slices.EqualFunc(got, tt.want, func(a, b ItemCursor) bool { return a.Compare(b) == 0 }) {
+				t.Errorf("SortItems() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package movie
+
+import (
+	"errors"
+	"time"
+)
+
+// Window is the span of reviews a chart is computed from.
+type Window string
+
+const (
+	WindowWeek    Window = "week"
+	WindowMonth   Window = "month"
+	WindowAllTime Window = "all"
+)
+
+var ErrInvalidWindow = errors.New("window must be week, month or all")
+
+func ParseWindow(s string) (Window, error) {
+	switch w := Window(s); w {
+	case WindowWeek, WindowMonth, WindowAllTime:
+		return w, nil
+	case "":
+		return WindowAllTime, nil
+	default:
+		return "", ErrInvalidWindow
+	}
+}
+
+// Since returns the time the window starts at, relative to now.
+func (w Window) Since(now time.Time) time.Time {
+	switch w {
+	case WindowWeek:
+		return now.AddDate(0, 0, -7)
+	case WindowMonth:
+		return now.AddDate(0, -1, 0)
+	default:
+		return time.Time{}
+	}
+}
+
+// ChartEntry is a movie's place in a chart, with the review stats of the
+// chart's window. WeightedRating is IMDb's weighted rating, the average
+// pulled towards the mean of every rating as if the movie had a minimum
+// number of reviews more of it, so a movie with a couple of perfect reviews
+// doesn't outrank well reviewed classics.
+type ChartEntry struct {
+	Rank           int     `json:"rank"`
+	MovieId        int32   `json:"movie_id"`
+	ReviewCount    int64   `json:"review_count"`
+	AverageRating  float64 `json:"average_rating"`
+	WeightedRating float64 `json:"weighted_rating,omitempty"`
+	Movie          *Movie  `json:"movie,omitempty"`
+}
@@ -0,0 +1,56 @@
+package movie
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    Window
+		wantErr bool
+	}{
+		{s: "", want: WindowAllTime},
+		{s: "week", want: WindowWeek},
+		{s: "month", want: WindowMonth},
+		{s: "all", want: WindowAllTime},
+		{s: "year", wantErr: true},
+		{s: "Week", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseWindow(tt.s)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidWindow) {
+				t.Errorf("ParseWindow(%q) error = %v, want ErrInvalidWindow", tt.s, err)
+			}
+			continue
+		}
+
+		if err != nil || got != tt.want {
+			t.Errorf("ParseWindow(%q) = %q, %v, want %q", tt.s, got, err, tt.want)
+		}
+	}
+}
+
+func TestWindowSince(t *testing.T) {
+	now := time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		window Window
+		want   time.Time
+	}{
+		{window: WindowWeek, want: time.Date(2025, 3, 24, 12, 0, 0, 0, time.UTC)},
+		// March 31st a month back normalizes past February.
+		{window: WindowMonth, want: time.Date(2025, 3, 3, 12, 0, 0, 0, time.UTC)},
+		{window: WindowAllTime, want: time.Time{}},
+	}
+
+	for _, tt := range tests {
+		if got := tt.window.Since(now); !got.Equal(tt.want) {
+			t.Errorf("%q.Since(%v) = %v, want %v", tt.window, now, got, tt.want)
+		}
+	}
+}
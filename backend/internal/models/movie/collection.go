@@ -0,0 +1,55 @@
+package movie
+
+import (
+	"cmp"
+	"slices"
+)
+
+type Collection struct {
+	Id            int32     `json:"id"`
+	Name          string    `json:"name"`
+	Overview      string    `json:"overview"`
+	PosterPath    string    `json:"poster_path"`
+	PosterURLs    ImageURLs `json:"poster_urls,omitempty"`
+	BackdropPath  string    `json:"backdrop_path"`
+	BackdropURLs  ImageURLs `json:"backdrop_urls,omitempty"`
+	Parts         Movies    `json:"parts,omitempty"`
+	AverageRating float64   `json:"average_rating"`
+}
+
+func (c *Collection) ResolveImages(config ImageConfig) {
+	c.PosterURLs = config.Poster(c.PosterPath)
+	c.BackdropURLs = config.Backdrop(c.BackdropPath)
+	c.Parts.ResolveImages(config)
+}
+
+// SortPartsByReleaseDate orders parts oldest first, unreleased ones last.
+func (c *Collection) SortPartsByReleaseDate() {
+	slices.SortStableFunc(c.Parts, func(a, b Movie) int {
+		if a.ReleaseDate == "" || b.ReleaseDate == "" {
+			return cmp.Compare(len(b.ReleaseDate), len(a.ReleaseDate))
+		}
+		return cmp.Compare(a.ReleaseDate, b.ReleaseDate)
+	})
+}
+
+// SetLocalRatings replaces each part's vote average with its local weighted
+// rating, and sets the collection's to the mean over the rated parts.
+func (c *Collection) SetLocalRatings(ratings map[int32]float64) {
+	var sum float64
+	var rated int
+
+	for i, part := range c.Parts {
+		rating, ok := ratings[part.Id]
+		c.Parts[i].VoteAverage = rating
+		if ok {
+			sum += rating
+			rated++
+		}
+	}
+
+	c.AverageRating = 0
+	if rated > 0 {
+		c.AverageRating = sum / float64(rated)
+	}
+}
@@ -0,0 +1,73 @@
+package movie
+
+import (
+	"cmp"
+	"slices"
+)
+
+type CastMember struct {
+	Adult              bool      `json:"adult"`
+	Gender             int32     `json:"gender"`
+	Id                 int32     `json:"id"`
+	KnownForDepartment string    `json:"known_for_department"`
+	Name               string    `json:"name"`
+	OriginalName       string    `json:"original_name"`
+	Popularity         float64   `json:"popularity"`
+	ProfilePath        string    `json:"profile_path"`
+	ProfileURLs        ImageURLs `json:"profile_urls,omitempty"`
+	CastId             int32     `json:"cast_id"`
+	Character          string    `json:"character"`
+	CreditId           string    `json:"credit_id"`
+	Order              int32     `json:"order"`
+}
+
+type CrewMember struct {
+	Adult              bool      `json:"adult"`
+	Gender             int32     `json:"gender"`
+	Id                 int32     `json:"id"`
+	KnownForDepartment string    `json:"known_for_department"`
+	Name               string    `json:"name"`
+	OriginalName       string    `json:"original_name"`
+	Popularity         float64   `json:"popularity"`
+	ProfilePath        string    `json:"profile_path"`
+	ProfileURLs        ImageURLs `json:"profile_urls,omitempty"`
+	CreditId           string    `json:"credit_id"`
+	Department         string    `json:"department"`
+	Job                string    `json:"job"`
+}
+
+type Credits struct {
+	Id   int32        `json:"id"`
+	Cast []CastMember `json:"cast"`
+	Crew []CrewMember `json:"crew"`
+}
+
+func (c *Credits) ResolveImages(config ImageConfig) {
+	for i, member := range c.Cast {
+		c.Cast[i].ProfileURLs = config.Profile(member.ProfilePath)
+	}
+	for i, member := range c.Crew {
+		c.Crew[i].ProfileURLs = config.Profile(member.ProfilePath)
+	}
+}
+
+// GroupedCredits has the cast in billing order and the crew by department.
+type GroupedCredits struct {
+	Id   int32                   `json:"id"`
+	Cast []CastMember            `json:"cast"`
+	Crew map[string][]CrewMember `json:"crew"`
+}
+
+func (c Credits) Grouped() GroupedCredits {
+	cast := slices.Clone(c.Cast)
+	slices.SortStableFunc(cast, func(a, b CastMember) int {
+		return cmp.Compare(a.Order, b.Order)
+	})
+
+	crew := make(map[string][]CrewMember)
+	for _, member := range c.Crew {
+		crew[member.Department] = append(crew[member.Department], member)
+	}
+
+	return GroupedCredits{Id: c.Id, Cast: cast, Crew: crew}
+}
@@ -0,0 +1,10 @@
+package movie
+
+type ExternalIds struct {
+	Id          int32  `json:"id"`
+	IMDBId      string `json:"imdb_id"`
+	WikidataId  string `json:"wikidata_id"`
+	FacebookId  string `json:"facebook_id"`
+	InstagramId string `json:"instagram_id"`
+	TwitterId   string `json:"twitter_id"`
+}
@@ -0,0 +1,70 @@
+package movie
+
+import "strings"
+
+type Image struct {
+	AspectRatio float64   `json:"aspect_ratio"`
+	Height      int32     `json:"height"`
+	ISO639_1    string    `json:"iso_639_1"`
+	FilePath    string    `json:"file_path"`
+	VoteAverage float64   `json:"vote_average"`
+	VoteCount   int32     `json:"vote_count"`
+	Width       int32     `json:"width"`
+	URLs        ImageURLs `json:"urls,omitempty"`
+}
+
+type Images struct {
+	Id        int32   `json:"id"`
+	Backdrops []Image `json:"backdrops"`
+	Logos     []Image `json:"logos"`
+	Posters   []Image `json:"posters"`
+}
+
+// ImageURLs maps TMDB size names, like w342 or original, to full image URLs.
+type ImageURLs map[string]string
+
+// ImageConfig is the images section of TMDB's /configuration.
+type ImageConfig struct {
+	SecureBaseURL string   `json:"secure_base_url"`
+	BackdropSizes []string `json:"backdrop_sizes"`
+	LogoSizes     []string `json:"logo_sizes"`
+	PosterSizes   []string `json:"poster_sizes"`
+	ProfileSizes  []string `json:"profile_sizes"`
+	StillSizes    []string `json:"still_sizes"`
+}
+
+// ImageResolver is implemented by models holding TMDB image paths.
+type ImageResolver interface {
+	ResolveImages(c ImageConfig)
+}
+
+func (c ImageConfig) urls(path string, sizes []string) ImageURLs {
+	if path == "" || c.SecureBaseURL == "" {
+		return nil
+	}
+
+	base := strings.TrimSuffix(c.SecureBaseURL, "/")
+	urls := make(ImageURLs, len(sizes))
+	for _, size := range sizes {
+		urls[size] = base + "/" + size + path
+	}
+
+	return urls
+}
+
+func (c ImageConfig) Backdrop(path string) ImageURLs { return c.urls(path, c.BackdropSizes) }
+func (c ImageConfig) Logo(path string) ImageURLs     { return c.urls(path, c.LogoSizes) }
+func (c ImageConfig) Poster(path string) ImageURLs   { return c.urls(path, c.PosterSizes) }
+func (c ImageConfig) Profile(path string) ImageURLs  { return c.urls(path, c.ProfileSizes) }
+
+func (i *Images) ResolveImages(c ImageConfig) {
+	resolve := func(images []Image, urls func(string) ImageURLs) {
+		for j := range images {
+			images[j].URLs = urls(images[j].FilePath)
+		}
+	}
+
+	resolve(i.Backdrops, c.Backdrop)
+	resolve(i.Logos, c.Logo)
+	resolve(i.Posters, c.Poster)
+}
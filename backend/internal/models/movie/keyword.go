@@ -0,0 +1,8 @@
+package movie
+
+type Keyword struct {
+	Id   int32  `json:"id"`
+	Name string `json:"name"`
+}
+
+type Keywords []Keyword
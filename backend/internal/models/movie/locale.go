@@ -0,0 +1,52 @@
+package movie
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Locale selects the language and region TMDB localizes results for.
+type Locale struct {
+	Language string // IETF tag, e.g. pt-BR
+	Region   string // ISO 3166-1, e.g. BR
+}
+
+var DefaultLocale = Locale{Language: "en-US", Region: "US"}
+
+// searchConfigs maps the languages with a Postgres text search configuration
+// to it.
+var searchConfigs = map[string]string{
+	"da": "danish",
+	"de": "german",
+	"en": "english",
+	"es": "spanish",
+	"fi": "finnish",
+	"fr": "french",
+	"hu": "hungarian",
+	"it": "italian",
+	"nb": "norwegian",
+	"nl": "dutch",
+	"no": "norwegian",
+	"pt": "portuguese",
+	"ro": "romanian",
+	"ru": "russian",
+	"sv": "swedish",
+	"tr": "turkish",
+}
+
+// SearchConfig returns the Postgres text search configuration for an IETF
+// language tag, simple when the language has none.
+func SearchConfig(language string) string {
+	base, _, _ := strings.Cut(language, "-")
+	if config, ok := searchConfigs[strings.ToLower(base)]; ok {
+		return config
+	}
+	return "simple"
+}
+
+// SearchConfigs returns every configuration SearchConfig can return, sorted.
+func SearchConfigs() []string {
+	configs := slices.Sorted(maps.Values(searchConfigs))
+	return slices.Insert(slices.Compact(configs), 0, "simple")
+}
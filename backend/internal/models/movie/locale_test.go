@@ -0,0 +1,39 @@
+package movie
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSearchConfig(t *testing.T) {
+	tests := []struct {
+		language string
+		want     string
+	}{
+		{language: "en-US", want: "english"},
+		{language: "pt-BR", want: "portuguese"},
+		{language: "de", want: "german"},
+		{language: "NB-no", want: "norwegian"},
+		{language: "ja-JP", want: "simple"},
+		{language: "", want: "simple"},
+	}
+
+	for _, tt := range tests {
+		if got := SearchConfig(tt.language); got != tt.want {
+			t.Errorf("SearchConfig(%q) = %q, want %q", tt.language, got, tt.want)
+		}
+	}
+}
+
+func TestSearchConfigs(t *testing.T) {
+	configs := SearchConfigs()
+
+	for _, language := range []string{"en", "nb", "no", "ja"} {
+		if config := SearchConfig(language); !slices.Contains(configs, config) {
+			t.Errorf("SearchConfigs() = %v, missing %q", configs, config)
+		}
+	}
+	if !slices.IsSorted(configs[1:]) || len(slices.Compact(slices.Clone(configs))) != len(configs) {
+		t.Errorf("SearchConfigs() = %v, want simple then sorted unique configurations", configs)
+	}
+}
@@ -6,10 +6,11 @@ type Genre struct {
 }
 
 type ProductionCompany struct {
-	Id            int32  `json:"id"`
-	LogoPath      string `json:"logo_path"`
-	Name          string `json:"name"`
-	OriginCountry string `json:"origin_country"`
+	Id            int32     `json:"id"`
+	LogoPath      string    `json:"logo_path"`
+	LogoURLs      ImageURLs `json:"logo_urls,omitempty"`
+	Name          string    `json:"name"`
+	OriginCountry string    `json:"origin_country"`
 }
 
 type ProductionCountry struct {
@@ -24,15 +25,18 @@ type SpokenLanguage struct {
 }
 
 type BelongsToCollection struct {
-	Id           int32  `json:"id"`
-	Name         string `json:"name"`
-	PosterPath   string `json:"poster_path"`
-	BackdropPath string `json:"backdrop_path"`
+	Id           int32     `json:"id"`
+	Name         string    `json:"name"`
+	PosterPath   string    `json:"poster_path"`
+	PosterURLs   ImageURLs `json:"poster_urls,omitempty"`
+	BackdropPath string    `json:"backdrop_path"`
+	BackdropURLs ImageURLs `json:"backdrop_urls,omitempty"`
 }
 
 type Movie struct {
 	Adult               bool                 `json:"adult"`
 	BackdropPath        string               `json:"backdrop_path"`
+	BackdropURLs        ImageURLs            `json:"backdrop_urls,omitempty"`
 	BelongsToCollection *BelongsToCollection `json:"belongs_to_collection,omitempty"`
 	Budget              int32                `json:"budget"`
 	Genres              []Genre              `json:"genres,omitempty"`
@@ -44,6 +48,7 @@ type Movie struct {
 	Overview            string               `json:"overview"`
 	Popularity          float64              `json:"popularity"`
 	PosterPath          string               `json:"poster_path"`
+	PosterURLs          ImageURLs            `json:"poster_urls,omitempty"`
 	ProductionCompanies []ProductionCompany  `json:"production_companies,omitempty"`
 	ProductionCountries []ProductionCountry  `json:"production_countries,omitempty"`
 	ReleaseDate         string               `json:"release_date"`
@@ -59,3 +64,34 @@ type Movie struct {
 }
 
 type Movies []Movie
+
+func (m *Movie) ResolveImages(c ImageConfig) {
+	m.PosterURLs = c.Poster(m.PosterPath)
+	m.BackdropURLs = c.Backdrop(m.BackdropPath)
+
+	for i, company := range m.ProductionCompanies {
+		m.ProductionCompanies[i].LogoURLs = c.Logo(company.LogoPath)
+	}
+
+	if m.BelongsToCollection != nil {
+		m.BelongsToCollection.PosterURLs = c.Poster(m.BelongsToCollection.PosterPath)
+		m.BelongsToCollection.BackdropURLs = c.Backdrop(m.BelongsToCollection.BackdropPath)
+	}
+}
+
+func (m Movies) ResolveImages(c ImageConfig) {
+	for i := range m {
+		m[i].ResolveImages(c)
+	}
+}
+
+type Genres []Genre
+
+func (g Genres) Contains(id int32) bool {
+	for _, genre := range g {
+		if genre.Id == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,20 @@
+package movie
+
+type WatchProvider struct {
+	DisplayPriority int32  `json:"display_priority"`
+	LogoPath        string `json:"logo_path"`
+	ProviderId      int32  `json:"provider_id"`
+	ProviderName    string `json:"provider_name"`
+}
+
+type CountryWatchProviders struct {
+	Link     string          `json:"link"`
+	Flatrate []WatchProvider `json:"flatrate,omitempty"`
+	Rent     []WatchProvider `json:"rent,omitempty"`
+	Buy      []WatchProvider `json:"buy,omitempty"`
+	Free     []WatchProvider `json:"free,omitempty"`
+	Ads      []WatchProvider `json:"ads,omitempty"`
+}
+
+// WatchProviders are keyed by ISO 3166-1 country code.
+type WatchProviders map[string]CountryWatchProviders
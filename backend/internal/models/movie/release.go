@@ -0,0 +1,43 @@
+package movie
+
+// Release types as defined by TMDB.
+const (
+	ReleasePremiere int32 = iota + 1
+	ReleaseTheatricalLimited
+	ReleaseTheatrical
+	ReleaseDigital
+	ReleasePhysical
+	ReleaseTV
+)
+
+type ReleaseDate struct {
+	Certification string   `json:"certification"`
+	Descriptors   []string `json:"descriptors"`
+	ISO639_1      string   `json:"iso_639_1"`
+	Note          string   `json:"note"`
+	ReleaseDate   string   `json:"release_date"`
+	Type          int32    `json:"type"`
+}
+
+type CountryReleaseDates struct {
+	ISO31661     string        `json:"iso_3166_1"`
+	ReleaseDates []ReleaseDate `json:"release_dates"`
+}
+
+type ReleaseDates []CountryReleaseDates
+
+// Certification returns the first non empty certification for region.
+func (r ReleaseDates) Certification(region string) string {
+	for _, country := range r {
+		if country.ISO31661 != region {
+			continue
+		}
+		for _, date := range country.ReleaseDates {
+			if date.Certification != "" {
+				return date.Certification
+			}
+		}
+	}
+
+	return ""
+}
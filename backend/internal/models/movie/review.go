@@ -6,6 +6,12 @@ import (
 	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/user"
 )
 
+const (
+	EventReviewCreated = "review.created"
+	EventReviewUpdated = "review.updated"
+	EventReviewDeleted = "review.deleted"
+)
+
 type Review struct {
 	Id        int32     `json:"id"`
 	MovieId   int32     `json:"movie_id"`
@@ -15,7 +21,9 @@ type Review struct {
 	Review    string    `json:"review"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	Language  string    `json:"-"` // text search configuration, see SearchConfig
 	User      user.User `json:"user"`
+	Movie     *Movie    `json:"movie,omitempty"`
 }
 
 type Reviews []Review
@@ -0,0 +1,14 @@
+package movie
+
+import "time"
+
+// TrendingParams weighs the activity trending scores are computed from. Each
+// review and watchlist add since Since counts for its weight, halved every
+// HalfLife, and the Limit best scored movies are kept.
+type TrendingParams struct {
+	Since           time.Time
+	HalfLife        time.Duration
+	ReviewWeight    float64
+	WatchlistWeight float64
+	Limit           int32
+}
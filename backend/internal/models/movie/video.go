@@ -1,48 +1,112 @@
 package movie
 
 import (
+	"cmp"
+	"fmt"
 	"slices"
 	"strings"
 )
 
+const (
+	SiteYouTube = "YouTube"
+	SiteVimeo   = "Vimeo"
+)
+
+// VideoSites are the sites we know how to embed.
+var VideoSites = []string{SiteYouTube, SiteVimeo}
+
 type Video struct {
-	Id          string `json:"id"`
-	ISO639_1    string `json:"iso_639_1"`
-	ISO3166_1   string `json:"iso_3166_1"`
-	Name        string `json:"name"`
-	Key         string `json:"key"`
-	Site        string `json:"site"`
-	Size        int32  `json:"size"` // defaults to 0
-	Type        string `json:"type"`
-	Official    bool   `json:"official"` // defaults to true
-	PublishedAt string `json:"published_at"`
+	Id           string `json:"id"`
+	ISO639_1     string `json:"iso_639_1"`
+	ISO3166_1    string `json:"iso_3166_1"`
+	Name         string `json:"name"`
+	Key          string `json:"key"`
+	Site         string `json:"site"`
+	Size         int32  `json:"size"` // defaults to 0
+	Type         string `json:"type"`
+	Official     bool   `json:"official"` // defaults to true
+	PublishedAt  string `json:"published_at"`
+	EmbedURL     string `json:"embed_url,omitempty"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
 }
 
 type Videos []Video
 
-func (v *Videos) FilterTrailersAndTeasersOnYT() {
-	var filtered Videos
-	for _, video := range *v {
-		if video.Site == "YouTube" && (video.Type == "Trailer" || video.Type == "Teaser") && video.Official {
-			filtered = append(filtered, video)
-		}
+// VideoFilter keeps videos matching any of Types and any of Sites. Empty
+// lists match everything, and a nil Official matches both values.
+type VideoFilter struct {
+	Types    []string
+	Sites    []string
+	Official *bool
+}
+
+// DefaultVideoFilter keeps official YouTube trailers and teasers.
+func DefaultVideoFilter() VideoFilter {
+	official := true
+	return VideoFilter{
+		Types:    []string{"Trailer", "Teaser"},
+		Sites:    []string{SiteYouTube},
+		Official: &official,
 	}
+}
+
+func (f VideoFilter) matches(v Video) bool {
+	matchesAny := func(values []string, value string) bool {
+		return len(values) == 0 || slices.ContainsFunc(values, func(s string) bool {
+			return strings.EqualFold(s, value)
+		})
+	}
+
+	return matchesAny(f.Types, v.Type) && matchesAny(f.Sites, v.Site) &&
+		(f.Official == nil || *f.Official == v.Official)
+}
 
-	*v = filtered
+func (v *Videos) Filter(f VideoFilter) {
+	*v = slices.DeleteFunc(*v, func(video Video) bool {
+		return !f.matches(video)
+	})
 }
 
+// SortByRelevance puts trailers before teasers before anything else, then
+// official videos first, then higher resolutions and finally newer ones.
 func (v Videos) SortByRelevance() {
-	weight := func(v Video) int {
-		if v.Type != "Trailer" {
+	typeRank := func(v Video) int {
+		switch v.Type {
+		case "Trailer":
 			return 0
-		}
-		if !strings.Contains(strings.ToLower(v.Name), "official trailer") {
+		case "Teaser":
+			return 1
+		default:
 			return 2
 		}
+	}
+	officialRank := func(v Video) int {
+		if v.Official {
+			return 0
+		}
 		return 1
 	}
 
 	slices.SortStableFunc(v, func(a, b Video) int {
-		return weight(b) - weight(a)
+		return cmp.Or(
+			cmp.Compare(typeRank(a), typeRank(b)),
+			cmp.Compare(officialRank(a), officialRank(b)),
+			cmp.Compare(b.Size, a.Size),
+			cmp.Compare(b.PublishedAt, a.PublishedAt),
+		)
 	})
 }
+
+// SetURLs fills in the embed and thumbnail URLs of videos on known sites.
+// Vimeo has no static thumbnail URL, so its videos only get an embed URL.
+func (v Videos) SetURLs() {
+	for i, video := range v {
+		switch video.Site {
+		case SiteYouTube:
+			v[i].EmbedURL = fmt.Sprintf("https://www.youtube.com/embed/%s", video.Key)
+			v[i].ThumbnailURL = fmt.Sprintf("https://i.ytimg.com/vi/%s/hqdefault.jpg", video.Key)
+		case SiteVimeo:
+			v[i].EmbedURL = fmt.Sprintf("https://player.vimeo.com/video/%s", video.Key)
+		}
+	}
+}
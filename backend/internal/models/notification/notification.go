@@ -0,0 +1,44 @@
+package notification
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/user"
+)
+
+const (
+	TypeFollow = "follow"
+)
+
+// Notification tells a user that Actor did something involving them. Data
+// holds whatever else the Type needs to be rendered.
+type Notification struct {
+	Id        int32           `json:"id"`
+	UserId    int32           `json:"-"`
+	Actor     user.User       `json:"actor"`
+	Type      string          `json:"type"`
+	Data      json.RawMessage `json:"data"`
+	Read      bool            `json:"read"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+func New(userId int32, actor user.User, typ string, data any) (Notification, error) {
+	n := Notification{UserId: userId, Actor: actor, Type: typ, Data: json.RawMessage("{}")}
+	if data == nil {
+		return n, nil
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return n, err
+	}
+	n.Data = b
+
+	return n, nil
+}
+
+func NewFollow(followee int32, follower user.User) Notification {
+	n, _ := New(followee, follower, TypeFollow, nil)
+	return n
+}
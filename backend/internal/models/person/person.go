@@ -0,0 +1,79 @@
+package person
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/movie"
+)
+
+type Person struct {
+	Adult              bool            `json:"adult"`
+	AlsoKnownAs        []string        `json:"also_known_as,omitempty"`
+	Biography          string          `json:"biography"`
+	Birthday           string          `json:"birthday"`
+	Deathday           string          `json:"deathday"`
+	Gender             int32           `json:"gender"`
+	Homepage           string          `json:"homepage"`
+	Id                 int32           `json:"id"`
+	IMDBId             string          `json:"imdb_id"`
+	KnownForDepartment string          `json:"known_for_department"`
+	Name               string          `json:"name"`
+	PlaceOfBirth       string          `json:"place_of_birth"`
+	Popularity         float64         `json:"popularity"`
+	ProfilePath        string          `json:"profile_path"`
+	ProfileURLs        movie.ImageURLs `json:"profile_urls,omitempty"`
+	KnownFor           movie.Movies    `json:"known_for,omitempty"`
+	Filmography        *Filmography    `json:"movie_credits,omitempty"`
+}
+
+type CastCredit struct {
+	movie.Movie
+	Character string `json:"character"`
+	CreditId  string `json:"credit_id"`
+	Order     int32  `json:"order"`
+}
+
+type CrewCredit struct {
+	movie.Movie
+	CreditId   string `json:"credit_id"`
+	Department string `json:"department"`
+	Job        string `json:"job"`
+}
+
+type Filmography struct {
+	Cast []CastCredit `json:"cast"`
+	Crew []CrewCredit `json:"crew"`
+}
+
+func (p *Person) ResolveImages(c movie.ImageConfig) {
+	p.ProfileURLs = c.Profile(p.ProfilePath)
+	p.KnownFor.ResolveImages(c)
+
+	if p.Filmography != nil {
+		for i := range p.Filmography.Cast {
+			p.Filmography.Cast[i].ResolveImages(c)
+		}
+		for i := range p.Filmography.Crew {
+			p.Filmography.Crew[i].ResolveImages(c)
+		}
+	}
+}
+
+// SortByReleaseDate orders credits newest first, leaving unreleased movies
+// without a date at the top.
+func (f *Filmography) SortByReleaseDate() {
+	newestFirst := func(a, b string) int {
+		if a == "" || b == "" {
+			return cmp.Compare(len(a), len(b))
+		}
+		return cmp.Compare(b, a)
+	}
+
+	slices.SortStableFunc(f.Cast, func(a, b CastCredit) int {
+		return newestFirst(a.ReleaseDate, b.ReleaseDate)
+	})
+	slices.SortStableFunc(f.Crew, func(a, b CrewCredit) int {
+		return newestFirst(a.ReleaseDate, b.ReleaseDate)
+	})
+}
@@ -0,0 +1,30 @@
+package recommendation
+
+import "github.com/rodrigoaraujo46/flickmeter/backend/internal/models/movie"
+
+const (
+	SourceLocal = "local"
+	SourceTMDB  = "tmdb"
+)
+
+// Rating is a user's rating of a movie, as given in their review.
+type Rating struct {
+	UserId  int32
+	MovieId int32
+	Rating  int32
+}
+
+// Score is the rating a user is predicted to give a movie.
+type Score struct {
+	MovieId int32
+	Score   float64
+}
+
+// Recommendation is a movie recommended to a user. Score is only set for
+// the ones computed from local ratings.
+type Recommendation struct {
+	MovieId int32        `json:"movie_id"`
+	Score   float64      `json:"score,omitempty"`
+	Source  string       `json:"source"`
+	Movie   *movie.Movie `json:"movie,omitempty"`
+}
@@ -5,9 +5,15 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/cookies"
 	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/user"
 )
 
+const CookieName = "refresh"
+
+// MaxAge is how long a kept refresh token stays valid.
+const MaxAge = 720 * time.Hour
+
 type Refresh struct {
 	UUID    uuid.UUID
 	User    user.User
@@ -17,20 +23,12 @@ type Refresh struct {
 func New(uuid uuid.UUID, user user.User, keep bool) *Refresh {
 	var expires time.Time
 	if keep {
-		expires = time.Now().Add(720 * time.Hour)
+		expires = time.Now().Add(MaxAge)
 	}
 
 	return &Refresh{UUID: uuid, User: user, Expires: expires}
 }
 
-func (r Refresh) Cookie() *http.Cookie {
-	return &http.Cookie{
-		Name:     "refresh",
-		Path:     "/",
-		Value:    r.UUID.String(),
-		HttpOnly: true,
-		Secure:   true,
-		SameSite: http.SameSiteStrictMode,
-		Expires:  r.Expires,
-	}
+func (r Refresh) Cookie(p cookies.Policy) *http.Cookie {
+	return p.New(CookieName, r.UUID.String(), r.Expires)
 }
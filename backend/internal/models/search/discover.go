@@ -0,0 +1,28 @@
+package search
+
+// DiscoverSortOptions are the sort orders TMDB's discover API accepts.
+var DiscoverSortOptions = []string{
+	"popularity.asc", "popularity.desc",
+	"primary_release_date.asc", "primary_release_date.desc",
+	"vote_average.asc", "vote_average.desc",
+	"vote_count.asc", "vote_count.desc",
+	"revenue.asc", "revenue.desc",
+	"title.asc", "title.desc",
+	"original_title.asc", "original_title.desc",
+}
+
+// Discover filters movies. Zero values are left out of the request.
+type Discover struct {
+	Page             int32
+	Genres           []int32
+	ReleaseDateGTE   string // YYYY-MM-DD
+	ReleaseDateLTE   string // YYYY-MM-DD
+	RuntimeGTE       int32
+	RuntimeLTE       int32
+	OriginalLanguage string // ISO 639-1
+	VoteCountGTE     int32
+	VoteAverageGTE   float64 // 0 to 10
+	VoteAverageLTE   float64 // 0 to 10
+	SortBy           string
+	IncludeAdult     bool
+}
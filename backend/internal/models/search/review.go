@@ -0,0 +1,62 @@
+package search
+
+import (
+	"html"
+	"strings"
+
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/movie"
+)
+
+const (
+	highlightStart = "<mark>"
+	highlightStop  = "</mark>"
+)
+
+// ReviewParams filters a review search, zero ids match any movie or user.
+type ReviewParams struct {
+	Query     string
+	MovieId   int32
+	UserId    int32
+	MinRating int32
+	MaxRating int32
+	Page      int32
+}
+
+// ReviewHit is a review matching a search. TitleHighlight and Snippet are
+// HTML escaped, with the matched terms wrapped in <mark> tags.
+type ReviewHit struct {
+	movie.Review
+	TitleHighlight string  `json:"title_highlight"`
+	Snippet        string  `json:"snippet"`
+	Rank           float64 `json:"rank"`
+}
+
+func NewReviewHit(review movie.Review, titleHighlight, snippet string, rank float64) ReviewHit {
+	return ReviewHit{
+		Review:         review,
+		TitleHighlight: escapeHighlight(titleHighlight),
+		Snippet:        escapeHighlight(snippet),
+		Rank:           rank,
+	}
+}
+
+// escapeHighlight escapes everything in s but the highlight tags, which
+// wrap user written text.
+func escapeHighlight(s string) string {
+	parts := strings.Split(s, highlightStart)
+
+	var b strings.Builder
+	b.WriteString(html.EscapeString(parts[0]))
+	for _, part := range parts[1:] {
+		match, rest, found := strings.Cut(part, highlightStop)
+		if !found {
+			b.WriteString(html.EscapeString(highlightStart + part))
+			continue
+		}
+
+		b.WriteString(highlightStart + html.EscapeString(match) + highlightStop)
+		b.WriteString(html.EscapeString(rest))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,29 @@
+package search
+
+import "testing"
+
+func TestEscapeHighlight(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{name: "empty", s: "", want: ""},
+		{name: "plain", s: `Tom & "Jerry"`, want: "Tom &amp; &#34;Jerry&#34;"},
+		{name: "highlighted", s: "a <mark>great</mark> movie", want: "a <mark>great</mark> movie"},
+		{name: "several highlights", s: "<mark>a</mark> & <mark>b</mark>", want: "<mark>a</mark> &amp; <mark>b</mark>"},
+		{name: "markup in a match", s: "<mark><script></mark>", want: "<mark>&lt;script&gt;</mark>"},
+		{name: "markup around a match", s: "<b><mark>x</mark></b>", want: "&lt;b&gt;<mark>x</mark>&lt;/b&gt;"},
+		{name: "unclosed", s: "<mark>x", want: "&lt;mark&gt;x"},
+		{name: "stray stop", s: "x </mark> y", want: "x &lt;/mark&gt; y"},
+		{name: "stop after a match", s: "<mark>x</mark></mark>", want: "<mark>x</mark>&lt;/mark&gt;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeHighlight(tt.s); got != tt.want {
+				t.Errorf("escapeHighlight(%q) = %q, want %q", tt.s, got, tt.want)
+			}
+		})
+	}
+}
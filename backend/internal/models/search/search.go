@@ -0,0 +1,82 @@
+package search
+
+import (
+	"encoding/json"
+
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/movie"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/person"
+)
+
+const (
+	MediaMovie      = "movie"
+	MediaPerson     = "person"
+	MediaCollection = "collection"
+)
+
+type Params struct {
+	Query              string
+	Page               int32
+	Year               int32
+	PrimaryReleaseYear int32
+	IncludeAdult       bool
+}
+
+type Page[T any] struct {
+	Page         int32 `json:"page"`
+	Results      []T   `json:"results"`
+	TotalPages   int32 `json:"total_pages"`
+	TotalResults int32 `json:"total_results"`
+}
+
+func (p *Page[T]) ResolveImages(c movie.ImageConfig) {
+	for i := range p.Results {
+		if r, ok := any(&p.Results[i]).(movie.ImageResolver); ok {
+			r.ResolveImages(c)
+		}
+	}
+}
+
+// Result is a single multi search hit, only the field named by MediaType is set.
+type Result struct {
+	MediaType  string            `json:"media_type"`
+	Movie      *movie.Movie      `json:"movie,omitempty"`
+	Person     *person.Person    `json:"person,omitempty"`
+	Collection *movie.Collection `json:"collection,omitempty"`
+}
+
+func (r *Result) ResolveImages(c movie.ImageConfig) {
+	switch {
+	case r.Movie != nil:
+		r.Movie.ResolveImages(c)
+	case r.Person != nil:
+		r.Person.ResolveImages(c)
+	case r.Collection != nil:
+		r.Collection.ResolveImages(c)
+	}
+}
+
+// UnmarshalJSON decodes TMDB's flat multi search objects. Media types other
+// than movies, people and collections are left empty.
+func (r *Result) UnmarshalJSON(b []byte) error {
+	var media struct {
+		MediaType string `json:"media_type"`
+	}
+	if err := json.Unmarshal(b, &media); err != nil {
+		return err
+	}
+
+	r.MediaType = media.MediaType
+	switch media.MediaType {
+	case MediaMovie:
+		r.Movie = &movie.Movie{}
+		return json.Unmarshal(b, r.Movie)
+	case MediaPerson:
+		r.Person = &person.Person{}
+		return json.Unmarshal(b, r.Person)
+	case MediaCollection:
+		r.Collection = &movie.Collection{}
+		return json.Unmarshal(b, r.Collection)
+	default:
+		return nil
+	}
+}
@@ -2,10 +2,14 @@ package session
 
 import (
 	"net/http"
+	"time"
 
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/cookies"
 	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/user"
 )
 
+const CookieName = "session"
+
 type Session struct {
 	UUID string    `json:"uuid"`
 	User user.User `json:"user"`
@@ -18,13 +22,6 @@ func New(uuid string, user user.User) *Session {
 	}
 }
 
-func (r Session) Cookie() *http.Cookie {
-	return &http.Cookie{
-		Name:     "session",
-		Path:     "/",
-		Value:    r.UUID,
-		HttpOnly: true,
-		Secure:   true,
-		SameSite: http.SameSiteStrictMode,
-	}
+func (r Session) Cookie(p cookies.Policy) *http.Cookie {
+	return p.New(CookieName, r.UUID, time.Time{})
 }
@@ -0,0 +1,19 @@
+package user
+
+import "math"
+
+// Compatibility is how alike a user's taste is to another's, judged by the
+// ratings they gave the movies both reviewed. Score is a percentage, left
+// unset until they reviewed enough movies in common to tell.
+type Compatibility struct {
+	User    User  `json:"user"`
+	Score   *int  `json:"score"`
+	Overlap int64 `json:"overlap"`
+}
+
+// NewCompatibility scores correlation, a Pearson correlation coefficient, as
+// a percentage from 0 for opposite tastes to 100 for the same.
+func NewCompatibility(u User, correlation float64, overlap int64) Compatibility {
+	score := int(math.Round((correlation + 1) * 50))
+	return Compatibility{User: u, Score: &score, Overlap: overlap}
+}
@@ -0,0 +1,29 @@
+package user
+
+import "testing"
+
+func TestNewCompatibility(t *testing.T) {
+	tests := []struct {
+		name        string
+		correlation float64
+		want        int
+	}{
+		{name: "opposite", correlation: -1, want: 0},
+		{name: "unrelated", correlation: 0, want: 50},
+		{name: "same", correlation: 1, want: 100},
+		{name: "rounds half up", correlation: 0.25, want: 63},
+		{name: "rounds down", correlation: -0.333, want: 33},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewCompatibility(User{Id: 7}, tt.correlation, 12)
+			if got.Score == nil || *got.Score != tt.want {
+				t.Fatalf("NewCompatibility(%v) score = %v, want %d", tt.correlation, got.Score, tt.want)
+			}
+			if got.User.Id != 7 || got.Overlap != 12 {
+				t.Errorf("NewCompatibility() = %+v, want the user and overlap kept", got)
+			}
+		})
+	}
+}
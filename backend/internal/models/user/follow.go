@@ -0,0 +1,13 @@
+package user
+
+import "time"
+
+type Follow struct {
+	User       User      `json:"user"`
+	FollowedAt time.Time `json:"followed_at"`
+}
+
+type FollowCounts struct {
+	Followers int64 `json:"followers"`
+	Following int64 `json:"following"`
+}
@@ -0,0 +1,49 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/movie"
+)
+
+const (
+	EventWatchlistAdded = "watchlist.added"
+
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusDead      = "dead"
+)
+
+// Events lists the event types webhooks can subscribe to.
+var Events = []string{
+	movie.EventReviewCreated,
+	movie.EventReviewUpdated,
+	movie.EventReviewDeleted,
+	EventWatchlistAdded,
+}
+
+// Webhook subscribes URL to Events. Secret signs every payload sent to it and
+// is only shown when the webhook is created.
+type Webhook struct {
+	Id        int32     `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Delivery is an event waiting in the outbox to be sent to a webhook, or one
+// that was sent or given up on.
+type Delivery struct {
+	Id            int32           `json:"id"`
+	WebhookId     int32           `json:"webhook_id"`
+	EventType     string          `json:"event_type"`
+	Payload       json.RawMessage `json:"payload"`
+	Status        string          `json:"status"`
+	Attempts      int32           `json:"attempts"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	LastError     string          `json:"last_error,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
@@ -6,16 +6,23 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
-	"github.com/labstack/echo/v4"
-	"github.com/rodrigoaraujo46/assert"
 	"github.com/rodrigoaraujo46/flickmeter/backend/internal/config"
 	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/movie"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/person"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/search"
+	"golang.org/x/sync/errgroup"
 )
 
 type client struct {
-	http *http.Client
+	http        *http.Client
+	baseURL     string
+	genres      *ttlCache[string, movie.Genres]
+	imageConfig *ttlCache[struct{}, movie.ImageConfig]
 }
 
 type authTransport struct {
@@ -31,187 +38,302 @@ func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 
 func NewClient(c config.MovieAPI) *client {
 	httpClient := &http.Client{
-		Timeout:   time.Second,
-		Transport: &authTransport{base: http.DefaultTransport, token: c.Token},
+		Transport: &retryTransport{
+			base:       &authTransport{base: http.DefaultTransport, token: c.Token},
+			timeout:    c.Timeout,
+			maxRetries: c.MaxRetries,
+			breaker:    newBreaker(c.BreakerThreshold, c.BreakerCooldown),
+		},
 	}
 
 	return &client{
-		http: httpClient,
+		http:        httpClient,
+		baseURL:     strings.TrimSuffix(c.BaseURL, "/"),
+		genres:      newTTLCache[string, movie.Genres](24 * time.Hour),
+		imageConfig: newTTLCache[struct{}, movie.ImageConfig](48 * time.Hour),
 	}
 }
 
-func (c client) GetTrending(ctx context.Context, weekly bool) (movie.Movies, error) {
-	url := "https://api.themoviedb.org/3/trending/movie/day?language=en-US"
-	if weekly {
-		url = "https://api.themoviedb.org/3/trending/movie/week?language=en-US"
+// endpoint returns the url for path, localized for locale.
+func (c client) endpoint(path string, locale movie.Locale, query url.Values) string {
+	if query == nil {
+		query = url.Values{}
+	}
+	if locale.Language != "" {
+		query.Set("language", locale.Language)
+	}
+	if locale.Region != "" {
+		query.Set("region", locale.Region)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	return c.baseURL + path + "?" + query.Encode()
+}
+
+// get fetches path and decodes the JSON response body into a T. When T holds
+// image paths, they are resolved into full URLs if the image configuration
+// can be fetched.
+func get[T any](ctx context.Context, c client, path string, locale movie.Locale, query url.Values) (T, error) {
+	var result T
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint(path, locale, query), nil)
 	if err != nil {
-		return nil, err
+		return result, err
 	}
 
 	res, err := c.http.Do(req)
 	if err != nil {
-		return nil, err
+		return result, err
 	}
-
-	defer func() {
-		err := res.Body.Close()
-		assert.NoError(err, "movieAPI.GetTrending")
-	}()
+	defer func() { _ = res.Body.Close() }()
 
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
-		return nil, err
+		return result, err
 	}
 
 	if res.StatusCode != http.StatusOK {
-		var jsonMap map[string]any
-		if err := json.Unmarshal(body, &jsonMap); err != nil {
-			return nil, err
-		}
-		return nil, echo.NewHTTPError(res.StatusCode, jsonMap["status_message"])
+		return result, newStatusError(res, body)
 	}
 
-	var movieRes struct {
-		Results movie.Movies `json:"results"`
+	if err := json.Unmarshal(body, &result); err != nil {
+		return result, fmt.Errorf("movieapi: decoding %s: %w", path, err)
 	}
 
-	err = json.Unmarshal(body, &movieRes)
-	if err != nil {
-		return nil, err
+	if resolver, ok := any(&result).(movie.ImageResolver); ok {
+		if config, err := c.GetImageConfig(ctx); err == nil {
+			resolver.ResolveImages(config)
+		}
 	}
 
-	return movieRes.Results, nil
+	return result, nil
 }
 
-func (c client) GetMovie(ctx context.Context, id int32) (movie.Movie, error) {
-	url := fmt.Sprintf("https://api.themoviedb.org/3/movie/%d?language=en-US", id)
+type results[T any] struct {
+	Results T `json:"results"`
+}
 
-	var empty movie.Movie
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return empty, err
+func (r *results[T]) ResolveImages(c movie.ImageConfig) {
+	if resolver, ok := any(&r.Results).(movie.ImageResolver); ok {
+		resolver.ResolveImages(c)
 	}
+}
 
-	res, err := c.http.Do(req)
-	if err != nil {
-		return empty, err
+// GetImageConfig returns the cached image configuration, fetching it if it
+// has expired.
+func (c client) GetImageConfig(ctx context.Context) (movie.ImageConfig, error) {
+	if config, ok := c.imageConfig.get(struct{}{}); ok {
+		return config, nil
 	}
 
-	defer func() {
-		err := res.Body.Close()
-		assert.NoError(err, "movieAPI.GetMovie")
-	}()
+	return c.RefreshImageConfig(ctx)
+}
 
-	body, err := io.ReadAll(res.Body)
+// RefreshImageConfig fetches TMDB's image configuration into the cache.
+func (c client) RefreshImageConfig(ctx context.Context) (movie.ImageConfig, error) {
+	res, err := get[struct {
+		Images movie.ImageConfig `json:"images"`
+	}](ctx, c, "/configuration", movie.Locale{}, nil)
 	if err != nil {
-		return empty, err
+		return res.Images, err
 	}
 
-	if res.StatusCode != http.StatusOK {
-		var jsonMap map[string]any
-		if err := json.Unmarshal(body, &jsonMap); err != nil {
-			return empty, err
-		}
-		return empty, echo.NewHTTPError(res.StatusCode, jsonMap["status_message"])
-	}
+	c.imageConfig.set(struct{}{}, res.Images)
+	return res.Images, nil
+}
 
-	var movie movie.Movie
-	err = json.Unmarshal(body, &movie)
-	if err != nil {
-		return empty, err
+func pageQuery(page int32) url.Values {
+	return url.Values{"page": {strconv.Itoa(int(max(page, 1)))}}
+}
+
+func (c client) GetTrending(ctx context.Context, weekly bool, locale movie.Locale) (movie.Movies, error) {
+	path := "/trending/movie/day"
+	if weekly {
+		path = "/trending/movie/week"
 	}
 
-	return movie, nil
+	res, err := get[results[movie.Movies]](ctx, c, path, locale, nil)
+	return res.Results, err
 }
 
-func (c client) GetVideos(ctx context.Context, id int32) (movie.Videos, error) {
-	url := fmt.Sprintf("https://api.themoviedb.org/3/movie/%d/videos?language=en-US", id)
+func (c client) GetMovie(ctx context.Context, id int32, locale movie.Locale) (movie.Movie, error) {
+	return get[movie.Movie](ctx, c, fmt.Sprintf("/movie/%d", id), locale, nil)
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
+func (c client) GetVideos(ctx context.Context, id int32, locale movie.Locale) (movie.Videos, error) {
+	res, err := get[results[movie.Videos]](ctx, c, fmt.Sprintf("/movie/%d/videos", id), locale, nil)
+	return res.Results, err
+}
 
-	res, err := c.http.Do(req)
-	if err != nil {
-		return nil, err
+func searchQuery(p search.Params) url.Values {
+	query := pageQuery(p.Page)
+	query.Set("query", p.Query)
+	query.Set("include_adult", strconv.FormatBool(p.IncludeAdult))
+	if p.Year != 0 {
+		query.Set("year", strconv.Itoa(int(p.Year)))
+	}
+	if p.PrimaryReleaseYear != 0 {
+		query.Set("primary_release_year", strconv.Itoa(int(p.PrimaryReleaseYear)))
 	}
 
-	defer func() {
-		err := res.Body.Close()
-		assert.NoError(err, "movieAPI.GetMovie")
-	}()
+	return query
+}
 
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
+func (c client) SearchMovies(ctx context.Context, p search.Params, locale movie.Locale) (search.Page[movie.Movie], error) {
+	return get[search.Page[movie.Movie]](ctx, c, "/search/movie", locale, searchQuery(p))
+}
+
+func (c client) SearchPeople(ctx context.Context, p search.Params, locale movie.Locale) (search.Page[person.Person], error) {
+	return get[search.Page[person.Person]](ctx, c, "/search/person", locale, searchQuery(p))
+}
+
+func (c client) SearchCollections(ctx context.Context, p search.Params, locale movie.Locale) (search.Page[movie.Collection], error) {
+	return get[search.Page[movie.Collection]](ctx, c, "/search/collection", locale, searchQuery(p))
+}
+
+// SearchMulti searches movies, people and collections at once. TMDB's multi
+// search doesn't cover collections, so those are fetched alongside it and
+// appended to each page.
+func (c client) SearchMulti(ctx context.Context, p search.Params, locale movie.Locale) (search.Page[search.Result], error) {
+	var multi search.Page[search.Result]
+	var collections search.Page[movie.Collection]
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() (err error) {
+		multi, err = get[search.Page[search.Result]](gctx, c, "/search/multi", locale, searchQuery(p))
+		return err
+	})
+	g.Go(func() (err error) {
+		collections, err = c.SearchCollections(gctx, p, locale)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return multi, err
 	}
 
-	if res.StatusCode != http.StatusOK {
-		var jsonMap map[string]any
-		if err := json.Unmarshal(body, &jsonMap); err != nil {
-			return nil, err
+	results := make([]search.Result, 0, len(multi.Results)+len(collections.Results))
+	for _, r := range multi.Results {
+		if r.Movie != nil || r.Person != nil {
+			results = append(results, r)
 		}
-		return nil, echo.NewHTTPError(res.StatusCode, jsonMap["status_message"])
 	}
-
-	var videoRes struct {
-		Results movie.Videos `json:"results"`
+	for _, collection := range collections.Results {
+		results = append(results, search.Result{MediaType: search.MediaCollection, Collection: &collection})
 	}
 
-	err = json.Unmarshal(body, &videoRes)
-	if err != nil {
-		return nil, err
-	}
+	return search.Page[search.Result]{
+		Page:         multi.Page,
+		Results:      results,
+		TotalPages:   max(multi.TotalPages, collections.TotalPages),
+		TotalResults: multi.TotalResults + collections.TotalResults,
+	}, nil
+}
 
-	return videoRes.Results, nil
+func (c client) GetCredits(ctx context.Context, id int32, locale movie.Locale) (movie.Credits, error) {
+	return get[movie.Credits](ctx, c, fmt.Sprintf("/movie/%d/credits", id), locale, nil)
 }
 
-func (c client) Search(ctx context.Context, query string) (movie.Movies, error) {
-	const url = "https://api.themoviedb.org/3/search/movie"
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
+// GetImages returns the images in the locale's language and those with no
+// language at all, such as most backdrops.
+func (c client) GetImages(ctx context.Context, id int32, locale movie.Locale) (movie.Images, error) {
+	lang, _, _ := strings.Cut(locale.Language, "-")
+	query := url.Values{"include_image_language": {lang + ",null"}}
 
-	q := req.URL.Query()
-	q.Set("query", query)
-	req.URL.RawQuery = q.Encode()
+	return get[movie.Images](ctx, c, fmt.Sprintf("/movie/%d/images", id), movie.Locale{}, query)
+}
 
-	res, err := c.http.Do(req)
-	if err != nil {
-		return nil, err
-	}
+func (c client) GetRecommendations(ctx context.Context, id, page int32, locale movie.Locale) (movie.Movies, error) {
+	res, err := get[results[movie.Movies]](ctx, c, fmt.Sprintf("/movie/%d/recommendations", id), locale, pageQuery(page))
+	return res.Results, err
+}
 
-	defer func() {
-		err := res.Body.Close()
-		assert.NoError(err, "movieAPI.Search")
-	}()
+func (c client) GetSimilar(ctx context.Context, id, page int32, locale movie.Locale) (movie.Movies, error) {
+	res, err := get[results[movie.Movies]](ctx, c, fmt.Sprintf("/movie/%d/similar", id), locale, pageQuery(page))
+	return res.Results, err
+}
 
-	body, err := io.ReadAll(res.Body)
+func (c client) GetReleaseDates(ctx context.Context, id int32) (movie.ReleaseDates, error) {
+	res, err := get[results[movie.ReleaseDates]](ctx, c, fmt.Sprintf("/movie/%d/release_dates", id), movie.Locale{}, nil)
+	return res.Results, err
+}
+
+func (c client) GetKeywords(ctx context.Context, id int32) (movie.Keywords, error) {
+	res, err := get[struct {
+		Keywords movie.Keywords `json:"keywords"`
+	}](ctx, c, fmt.Sprintf("/movie/%d/keywords", id), movie.Locale{}, nil)
+	return res.Keywords, err
+}
+
+func (c client) GetWatchProviders(ctx context.Context, id int32) (movie.WatchProviders, error) {
+	res, err := get[results[movie.WatchProviders]](ctx, c, fmt.Sprintf("/movie/%d/watch/providers", id), movie.Locale{}, nil)
+	return res.Results, err
+}
+
+func (c client) GetExternalIds(ctx context.Context, id int32) (movie.ExternalIds, error) {
+	return get[movie.ExternalIds](ctx, c, fmt.Sprintf("/movie/%d/external_ids", id), movie.Locale{}, nil)
+}
+
+// GetPerson returns the person together with their movie credits.
+func (c client) GetPerson(ctx context.Context, id int32, locale movie.Locale) (person.Person, error) {
+	query := url.Values{"append_to_response": {"movie_credits"}}
+	return get[person.Person](ctx, c, fmt.Sprintf("/person/%d", id), locale, query)
+}
+
+// GetGenres returns TMDB's movie genres, cached per language.
+func (c client) GetGenres(ctx context.Context, locale movie.Locale) (movie.Genres, error) {
+	if genres, ok := c.genres.get(locale.Language); ok {
+		return genres, nil
+	}
+
+	res, err := get[struct {
+		Genres movie.Genres `json:"genres"`
+	}](ctx, c, "/genre/movie/list", movie.Locale{Language: locale.Language}, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	if res.StatusCode != http.StatusOK {
-		var jsonMap map[string]any
-		if err := json.Unmarshal(body, &jsonMap); err != nil {
-			return nil, err
+	c.genres.set(locale.Language, res.Genres)
+	return res.Genres, nil
+}
+
+func (c client) Discover(ctx context.Context, d search.Discover, locale movie.Locale) (search.Page[movie.Movie], error) {
+	query := pageQuery(d.Page)
+	query.Set("include_adult", strconv.FormatBool(d.IncludeAdult))
+
+	set := func(key, value string) {
+		if value != "" {
+			query.Set(key, value)
 		}
-		return nil, echo.NewHTTPError(res.StatusCode, jsonMap["status_message"])
 	}
-
-	var movieRes struct {
-		Results movie.Movies `json:"results"`
+	setInt := func(key string, value int32) {
+		if value != 0 {
+			query.Set(key, strconv.Itoa(int(value)))
+		}
+	}
+	setFloat := func(key string, value float64) {
+		if value != 0 {
+			query.Set(key, strconv.FormatFloat(value, 'f', -1, 64))
+		}
 	}
 
-	err = json.Unmarshal(body, &movieRes)
-	if err != nil {
-		return nil, err
+	genres := make([]string, 0, len(d.Genres))
+	for _, id := range d.Genres {
+		genres = append(genres, strconv.Itoa(int(id)))
 	}
 
-	return movieRes.Results, nil
+	set("with_genres", strings.Join(genres, ","))
+	set("primary_release_date.gte", d.ReleaseDateGTE)
+	set("primary_release_date.lte", d.ReleaseDateLTE)
+	setInt("with_runtime.gte", d.RuntimeGTE)
+	setInt("with_runtime.lte", d.RuntimeLTE)
+	set("with_original_language", d.OriginalLanguage)
+	setInt("vote_count.gte", d.VoteCountGTE)
+	setFloat("vote_average.gte", d.VoteAverageGTE)
+	setFloat("vote_average.lte", d.VoteAverageLTE)
+	set("sort_by", d.SortBy)
+
+	return get[search.Page[movie.Movie]](ctx, c, "/discover/movie", locale, query)
+}
+
+func (c client) GetCollection(ctx context.Context, id int32, locale movie.Locale) (movie.Collection, error) {
+	return get[movie.Collection](ctx, c, fmt.Sprintf("/collection/%d", id), locale, nil)
 }
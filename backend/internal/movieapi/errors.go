@@ -0,0 +1,77 @@
+package movieapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrNotFound    = errors.New("movieapi: not found")
+	ErrRateLimited = errors.New("movieapi: rate limited")
+	ErrUnavailable = errors.New("movieapi: upstream unavailable")
+)
+
+// StatusError is returned for any non 200 response from TMDB. It unwraps to
+// ErrNotFound, ErrRateLimited or ErrUnavailable where one applies.
+type StatusError struct {
+	StatusCode int
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("movieapi: %d %s", e.StatusCode, e.Message)
+}
+
+func (e *StatusError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode >= http.StatusInternalServerError:
+		return ErrUnavailable
+	default:
+		return nil
+	}
+}
+
+// newStatusError builds a StatusError from a response, whose body may or may
+// not be TMDB's JSON error object.
+func newStatusError(res *http.Response, body []byte) *StatusError {
+	var tmdbErr struct {
+		StatusMessage string `json:"status_message"`
+	}
+
+	message := http.StatusText(res.StatusCode)
+	if json.Unmarshal(body, &tmdbErr) == nil && tmdbErr.StatusMessage != "" {
+		message = tmdbErr.StatusMessage
+	}
+
+	return &StatusError{
+		StatusCode: res.StatusCode,
+		Message:    message,
+		RetryAfter: parseRetryAfter(res.Header.Get("Retry-After")),
+	}
+}
+
+// parseRetryAfter accepts both the delay-seconds and the HTTP-date forms.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+
+	return 0
+}
@@ -0,0 +1,95 @@
+package movieapi
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		min   time.Duration
+		max   time.Duration
+	}{
+		{name: "missing", value: "", min: 0, max: 0},
+		{name: "seconds", value: "5", min: 5 * time.Second, max: 5 * time.Second},
+		{name: "negative seconds", value: "-5", min: 0, max: 0},
+		{name: "future date", value: time.Now().Add(time.Minute).UTC().Format(http.TimeFormat), min: 58 * time.Second, max: time.Minute},
+		{name: "past date", value: "Wed, 21 Oct 2015 07:28:00 GMT", min: 0, max: 0},
+		{name: "garbage", value: "soon", min: 0, max: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value); got < tt.min || got > tt.max {
+				t.Errorf("parseRetryAfter(%q) = %v, want between %v and %v", tt.value, got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestNewStatusError(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		retryAfter  string
+		body        string
+		wantMessage string
+		wantRetry   time.Duration
+		wantErr     error
+	}{
+		{
+			name:        "not found",
+			status:      http.StatusNotFound,
+			body:        `{"status_code":34,"status_message":"The resource you requested could not be found."}`,
+			wantMessage: "The resource you requested could not be found.",
+			wantErr:     ErrNotFound,
+		},
+		{
+			name:        "rate limited",
+			status:      http.StatusTooManyRequests,
+			retryAfter:  "3",
+			wantMessage: "Too Many Requests",
+			wantRetry:   3 * time.Second,
+			wantErr:     ErrRateLimited,
+		},
+		{
+			name:        "unavailable",
+			status:      http.StatusServiceUnavailable,
+			body:        "<html>down</html>",
+			wantMessage: "Service Unavailable",
+			wantErr:     ErrUnavailable,
+		},
+		{
+			name:        "unauthorized",
+			status:      http.StatusUnauthorized,
+			body:        `{"status_message":"Invalid API key"}`,
+			wantMessage: "Invalid API key",
+		},
+	}
+
+	sentinels := []error{ErrNotFound, ErrRateLimited, ErrUnavailable}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			if tt.retryAfter != "" {
+				res.Header.Set("Retry-After", tt.retryAfter)
+			}
+
+			err := newStatusError(res, []byte(tt.body))
+			if err.StatusCode != tt.status || err.Message != tt.wantMessage || err.RetryAfter != tt.wantRetry {
+				t.Errorf("newStatusError() = %+v, want %d %q %v", err, tt.status, tt.wantMessage, tt.wantRetry)
+			}
+
+			for _, sentinel := range sentinels {
+				if want := sentinel == tt.wantErr; errors.Is(err, sentinel) != want {
+					t.Errorf("errors.Is(%v, %v) = %v, want %v", err, sentinel, !want, want)
+				}
+			}
+		})
+	}
+}
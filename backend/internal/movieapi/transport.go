@@ -0,0 +1,180 @@
+package movieapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	backoffBase = 100 * time.Millisecond
+	backoffMax  = 2 * time.Second
+)
+
+// retryTransport retries timeouts, network errors and 5xx responses with
+// jittered exponential backoff, and 429 responses after their Retry-After.
+// Every attempt gets its own timeout, and a breaker fails requests fast
+// while TMDB keeps failing.
+type retryTransport struct {
+	base       http.RoundTripper
+	timeout    time.Duration
+	maxRetries int
+	breaker    *breaker
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.breaker.allow() {
+		return nil, fmt.Errorf("%w: circuit open", ErrUnavailable)
+	}
+
+	for attempt := 0; ; attempt++ {
+		res, err := t.attempt(req)
+
+		wait, retry := t.shouldRetry(req.Context(), res, err, attempt)
+		if !retry {
+			t.record(req.Context(), res, err)
+			if err != nil && req.Context().Err() == nil {
+				err = fmt.Errorf("%w: %w", ErrUnavailable, err)
+			}
+			return res, err
+		}
+
+		if res != nil {
+			_, _ = io.Copy(io.Discard, res.Body)
+			_ = res.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			t.record(req.Context(), res, err)
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (t *retryTransport) attempt(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+
+	res, err := t.base.RoundTrip(req.Clone(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	res.Body = &cancelOnClose{ReadCloser: res.Body, cancel: cancel}
+	return res, nil
+}
+
+func (t *retryTransport) shouldRetry(ctx context.Context, res *http.Response, err error, attempt int) (time.Duration, bool) {
+	if attempt >= t.maxRetries || ctx.Err() != nil {
+		return 0, false
+	}
+
+	backoff := min(backoffBase<<attempt, backoffMax)
+	backoff = backoff/2 + rand.N(backoff/2+1)
+
+	switch {
+	case err != nil:
+		return backoff, true
+	case res.StatusCode == http.StatusTooManyRequests:
+		retryAfter := parseRetryAfter(res.Header.Get("Retry-After"))
+		if retryAfter > backoffMax {
+			return 0, false
+		}
+		return max(retryAfter, backoff), true
+	case res.StatusCode >= http.StatusInternalServerError:
+		return backoff, true
+	default:
+		return 0, false
+	}
+}
+
+// record reports the outcome of a request to the breaker. Requests the caller
+// gave up on say nothing about TMDB, so they are left out.
+func (t *retryTransport) record(ctx context.Context, res *http.Response, err error) {
+	if ctx.Err() != nil {
+		t.breaker.release()
+		return
+	}
+	t.breaker.record(isFailure(res, err))
+}
+
+func isFailure(res *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return res.StatusCode >= http.StatusInternalServerError
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// breaker opens after threshold consecutive failures. Once cooldown has
+// passed it lets a single request through, closing again if it succeeds.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newBreaker(threshold int, cooldown time.Duration) *breaker {
+	return &breaker{threshold: threshold, cooldown: cooldown}
+}
+
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return true
+	}
+
+	if b.probing || time.Since(b.openedAt) < b.cooldown {
+		return false
+	}
+
+	b.probing = true
+	return true
+}
+
+// release ends a request without an outcome, letting another probe through
+// if it was one.
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+func (b *breaker) record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+
+	if !failed {
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openedAt = time.Now()
+	}
+}
@@ -0,0 +1,256 @@
+package movieapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestTransport serves every request with the next of responses, repeating
+// the last one once they run out, and counts the requests it got.
+func newTestTransport(t *testing.T, b *breaker, responses ...func(w http.ResponseWriter, r *http.Request)) (*http.Client, string, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		responses[min(n, len(responses))-1](w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	client := &http.Client{Transport: &retryTransport{
+		base:       http.DefaultTransport,
+		timeout:    time.Second,
+		maxRetries: 2,
+		breaker:    b,
+	}}
+	return client, server.URL, &calls
+}
+
+func status(code int, headers ...string) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i+1 < len(headers); i += 2 {
+			w.Header().Set(headers[i], headers[i+1])
+		}
+		w.WriteHeader(code)
+	}
+}
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name       string
+		responses  []func(w http.ResponseWriter, r *http.Request)
+		wantStatus int
+		wantCalls  int32
+	}{
+		{
+			name:       "success",
+			responses:  []func(w http.ResponseWriter, r *http.Request){status(http.StatusOK)},
+			wantStatus: http.StatusOK,
+			wantCalls:  1,
+		},
+		{
+			name:       "5xx retried",
+			responses:  []func(w http.ResponseWriter, r *http.Request){status(http.StatusBadGateway), status(http.StatusOK)},
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+		},
+		{
+			name:       "5xx until retries run out",
+			responses:  []func(w http.ResponseWriter, r *http.Request){status(http.StatusServiceUnavailable)},
+			wantStatus: http.StatusServiceUnavailable,
+			wantCalls:  3,
+		},
+		{
+			name: "429 with seconds",
+			responses: []func(w http.ResponseWriter, r *http.Request){
+				status(http.StatusTooManyRequests, "Retry-After", "0"),
+				status(http.StatusOK),
+			},
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+		},
+		{
+			name: "429 with an HTTP date",
+			responses: []func(w http.ResponseWriter, r *http.Request){
+				status(http.StatusTooManyRequests, "Retry-After", time.Now().UTC().Format(http.TimeFormat)),
+				status(http.StatusOK),
+			},
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+		},
+		{
+			name: "429 waiting too long",
+			responses: []func(w http.ResponseWriter, r *http.Request){
+				status(http.StatusTooManyRequests, "Retry-After", "60"),
+			},
+			wantStatus: http.StatusTooManyRequests,
+			wantCalls:  1,
+		},
+		{
+			name:       "4xx not retried",
+			responses:  []func(w http.ResponseWriter, r *http.Request){status(http.StatusNotFound)},
+			wantStatus: http.StatusNotFound,
+			wantCalls:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, url, calls := newTestTransport(t, newBreaker(10, time.Minute), tt.responses...)
+
+			res, err := client.Get(url)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			_ = res.Body.Close()
+
+			if res.StatusCode != tt.wantStatus || calls.Load() != tt.wantCalls {
+				t.Errorf("Get() = %d after %d calls, want %d after %d", res.StatusCode, calls.Load(), tt.wantStatus, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryTransportBreaker(t *testing.T) {
+	const cooldown = 20 * time.Millisecond
+
+	get := func(client *http.Client, url string) (int, error) {
+		res, err := client.Get(url)
+		if err != nil {
+			return 0, err
+		}
+		_ = res.Body.Close()
+		return res.StatusCode, nil
+	}
+
+	tests := []struct {
+		name  string
+		probe func(w http.ResponseWriter, r *http.Request)
+		// after the probe, whether the breaker closed and whether another
+		// probe is let through right away.
+		wantClosed bool
+		wantAllow  bool
+	}{
+		{name: "probe succeeds", probe: status(http.StatusOK), wantClosed: true, wantAllow: true},
+		{name: "probe fails", probe: status(http.StatusInternalServerError), wantClosed: false, wantAllow: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBreaker(1, cooldown)
+			client, url, calls := newTestTransport(t, b, status(http.StatusInternalServerError), status(http.StatusInternalServerError), status(http.StatusInternalServerError), tt.probe)
+
+			if code, err := get(client, url); err != nil || code != http.StatusInternalServerError {
+				t.Fatalf("Get() = %d, %v, want 500", code, err)
+			}
+
+			_, err := get(client, url)
+			if !errors.Is(err, ErrUnavailable) || calls.Load() != 3 {
+				t.Fatalf("Get() while open = %v after %d calls, want ErrUnavailable without a call", err, calls.Load())
+			}
+
+			time.Sleep(cooldown)
+			client.Transport.(*retryTransport).maxRetries = 0
+			if _, err := get(client, url); err != nil {
+				t.Fatalf("Get() probe error = %v", err)
+			}
+
+			if closed := b.failures == 0; closed != tt.wantClosed {
+				t.Errorf("breaker closed = %v, want %v", closed, tt.wantClosed)
+			}
+			if allow := b.allow(); allow != tt.wantAllow {
+				t.Errorf("allow() = %v, want %v", allow, tt.wantAllow)
+			}
+		})
+	}
+}
+
+func TestRetryTransportCancelledProbe(t *testing.T) {
+	const cooldown = 20 * time.Millisecond
+
+	b := newBreaker(1, cooldown)
+	b.record(true)
+	openedAt := b.openedAt
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client, url, _ := newTestTransport(t, b, func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		<-r.Context().Done()
+	})
+
+	time.Sleep(cooldown)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Do(req); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Do() error = %v, want context.Canceled", err)
+	}
+
+	if b.failures != 1 || !b.openedAt.Equal(openedAt) {
+		t.Errorf("breaker = %d failures opened at %v, want it untouched", b.failures, b.openedAt)
+	}
+	if !b.allow() {
+		t.Error("allow() = false, want another probe let through")
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	transport := &retryTransport{maxRetries: 2}
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	response := func(code int, retryAfter string) *http.Response {
+		res := &http.Response{StatusCode: code, Header: http.Header{}}
+		if retryAfter != "" {
+			res.Header.Set("Retry-After", retryAfter)
+		}
+		return res
+	}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		res       *http.Response
+		err       error
+		attempt   int
+		wantRetry bool
+		minWait   time.Duration
+		maxWait   time.Duration
+	}{
+		{name: "network error", err: errors.New("reset"), wantRetry: true, minWait: backoffBase / 2, maxWait: backoffBase},
+		{name: "backoff grows", err: errors.New("reset"), attempt: 1, wantRetry: true, minWait: backoffBase, maxWait: 2 * backoffBase},
+		{name: "5xx", res: response(http.StatusBadGateway, ""), wantRetry: true, minWait: backoffBase / 2, maxWait: backoffBase},
+		{name: "429 after Retry-After", res: response(http.StatusTooManyRequests, "1"), wantRetry: true, minWait: time.Second, maxWait: time.Second},
+		{name: "429 too far off", res: response(http.StatusTooManyRequests, "3"), wantRetry: false},
+		{name: "4xx", res: response(http.StatusBadRequest, ""), wantRetry: false},
+		{name: "success", res: response(http.StatusOK, ""), wantRetry: false},
+		{name: "out of retries", err: errors.New("reset"), attempt: 2, wantRetry: false},
+		{name: "cancelled", ctx: cancelled, err: context.Canceled, wantRetry: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			wait, retry := transport.shouldRetry(ctx, tt.res, tt.err, tt.attempt)
+			if retry != tt.wantRetry {
+				t.Fatalf("shouldRetry() retry = %v, want %v", retry, tt.wantRetry)
+			}
+			if retry && (wait < tt.minWait || wait > tt.maxWait) {
+				t.Errorf("shouldRetry() wait = %v, want between %v and %v", wait, tt.minWait, tt.maxWait)
+			}
+		})
+	}
+}
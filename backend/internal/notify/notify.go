@@ -0,0 +1,88 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/events"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/notification"
+)
+
+const EventNotification = "notification"
+
+type (
+	// Channel delivers notifications to users, in app, by email and so on.
+	Channel interface {
+		Name() string
+		Deliver(ctx context.Context, n notification.Notification) (notification.Notification, error)
+	}
+
+	Store interface {
+		Create(ctx context.Context, n notification.Notification) (notification.Notification, error)
+	}
+
+	Publisher interface {
+		Publish(ctx context.Context, topic, typ string, data any) error
+	}
+
+	inApp struct {
+		store Store
+	}
+
+	realtime struct {
+		publisher Publisher
+	}
+
+	// Service sends every notification through its channels, in order. Each
+	// channel gets the notification as returned by the one before it, so a
+	// failing channel stops the ones after it.
+	Service struct {
+		channels []Channel
+	}
+)
+
+func NewService(channels ...Channel) *Service {
+	return &Service{channels}
+}
+
+// InApp saves notifications for users to list. It should come first, so
+// later channels see the saved notification.
+func InApp(store Store) Channel {
+	return inApp{store}
+}
+
+func (c inApp) Name() string {
+	return "in-app"
+}
+
+func (c inApp) Deliver(ctx context.Context, n notification.Notification) (notification.Notification, error) {
+	return c.store.Create(ctx, n)
+}
+
+// Realtime pushes notifications to the event streams of their users.
+func Realtime(publisher Publisher) Channel {
+	return realtime{publisher}
+}
+
+func (c realtime) Name() string {
+	return "realtime"
+}
+
+func (c realtime) Deliver(ctx context.Context, n notification.Notification) (notification.Notification, error) {
+	return n, c.publisher.Publish(ctx, events.UserTopic(n.UserId), EventNotification, n)
+}
+
+// Notify delivers n through every channel, up to the first that fails. A
+// notification that couldn't be saved isn't pushed, as users couldn't list
+// or mark it read.
+func (s *Service) Notify(ctx context.Context, n notification.Notification) error {
+	for _, c := range s.channels {
+		delivered, err := c.Deliver(ctx, n)
+		if err != nil {
+			return fmt.Errorf("notify: %s: %w", c.Name(), err)
+		}
+		n = delivered
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/events"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/notification"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/user"
+)
+
+type fakeStore struct {
+	err     error
+	created []notification.Notification
+}
+
+func (s *fakeStore) Create(ctx context.Context, n notification.Notification) (notification.Notification, error) {
+	if s.err != nil {
+		return n, s.err
+	}
+
+	n.Id = int32(len(s.created) + 1)
+	s.created = append(s.created, n)
+	return n, nil
+}
+
+type published struct {
+	topic, typ string
+	data       any
+}
+
+type fakePublisher struct {
+	err       error
+	published []published
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, topic, typ string, data any) error {
+	if p.err != nil {
+		return p.err
+	}
+
+	p.published = append(p.published, published{topic, typ, data})
+	return nil
+}
+
+func TestServiceNotify(t *testing.T) {
+	tests := []struct {
+		name          string
+		storeErr      error
+		publishErr    error
+		wantErr       string
+		wantCreated   int
+		wantPublished int
+	}{
+		{name: "every channel", wantCreated: 1, wantPublished: 1},
+		{name: "saving fails", storeErr: errors.New("down"), wantErr: "notify: in-app: down"},
+		{name: "pushing fails", publishErr: errors.New("down"), wantErr: "notify: realtime: down", wantCreated: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeStore{err: tt.storeErr}
+			publisher := &fakePublisher{err: tt.publishErr}
+			s := NewService(InApp(store), Realtime(publisher))
+
+			err := s.Notify(context.Background(), notification.NewFollow(7, user.User{Id: 3}))
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("Notify() error = %v, want %q", err, tt.wantErr)
+			}
+
+			if len(store.created) != tt.wantCreated || len(publisher.published) != tt.wantPublished {
+				t.Fatalf("Notify() saved %d and pushed %d, want %d and %d",
+					len(store.created), len(publisher.published), tt.wantCreated, tt.wantPublished)
+			}
+
+			if tt.wantPublished > 0 {
+				p := publisher.published[0]
+				n, ok := p.data.(notification.Notification)
+				if p.topic != events.UserTopic(7) || p.typ != EventNotification || !ok || n.Id != 1 {
+					t.Errorf("Notify() pushed %+v, want the saved notification to user 7", p)
+				}
+			}
+		})
+	}
+}
@@ -1,16 +1,31 @@
 package oauth
 
 import (
+	"net/http"
+
 	"github.com/gorilla/sessions"
 	"github.com/markbates/goth"
 	"github.com/markbates/goth/gothic"
 	"github.com/markbates/goth/providers/github"
 	"github.com/markbates/goth/providers/google"
 	"github.com/rodrigoaraujo46/flickmeter/backend/internal/config"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/cookies"
 )
 
-func StartOAuth(conf config.Gothic) {
-	gothic.Store = sessions.NewCookieStore([]byte(conf.CookieStoreKey))
+func StartOAuth(conf config.Gothic, policy cookies.Policy) {
+	store := sessions.NewCookieStore([]byte(conf.CookieStoreKey))
+	store.Options.Domain = policy.Domain
+	store.Options.Path = policy.Path
+	store.Options.Secure = policy.Secure
+	store.Options.HttpOnly = true
+	store.Options.SameSite = policy.SameSite
+	// The provider redirects back cross site, a strict cookie wouldn't be sent
+	// with the callback.
+	if store.Options.SameSite == http.SameSiteStrictMode {
+		store.Options.SameSite = http.SameSiteLaxMode
+	}
+	gothic.Store = store
+
 	googleProvider, githubProvider := conf.Providers["google"], conf.Providers["github"]
 	goth.UseProviders(
 		google.New(googleProvider.Client, googleProvider.Secret, googleProvider.Callback, "profile", "email"),
@@ -0,0 +1,202 @@
+package recommend
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"slices"
+
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/config"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/recommendation"
+)
+
+const (
+	// priorWeight is how many ratings of the global mean every user's mean
+	// starts with, so users with a single review still lean some way.
+	priorWeight = 2
+	// damping is added to the similarity behind every prediction, so movies
+	// backed by a single weak neighbor don't top the list.
+	damping = 1
+)
+
+type (
+	Store interface {
+		ReadRatings(ctx context.Context) ([]recommendation.Rating, error)
+		Replace(ctx context.Context, scores map[int32][]recommendation.Score) error
+	}
+
+	// Engine recommends movies with item-item collaborative filtering over
+	// the ratings of local reviews.
+	Engine struct {
+		store  Store
+		config config.Recommendations
+	}
+
+	rated struct {
+		movieId   int32
+		deviation float64
+	}
+
+	neighbor struct {
+		movieId    int32
+		similarity float64
+	}
+
+	pair struct {
+		dot, normA, normB float64
+		overlap           int
+	}
+)
+
+func NewEngine(store Store, c config.Recommendations) *Engine {
+	return &Engine{store, c}
+}
+
+// Recompute replaces every user's recommendations with ones computed from
+// the current ratings.
+func (e *Engine) Recompute(ctx context.Context) error {
+	ratings, err := e.store.ReadRatings(ctx)
+	if err != nil {
+		return err
+	}
+
+	return e.store.Replace(ctx, Compute(ratings, e.config))
+}
+
+// Compute predicts, for every user, the ratings they would give the movies
+// they haven't rated, keeping their best c.PerUser predictions above their
+// mean rating.
+//
+// Movies are compared by the adjusted cosine similarity of their ratings, by
+// the users who rated both, once at least c.MinOverlap did. Each prediction
+// is the user's mean plus the similarity weighted deviations of their
+// ratings of the c.Neighbors movies most similar to it.
+func Compute(ratings []recommendation.Rating, c config.Recommendations) map[int32][]recommendation.Score {
+	users, means := centre(ratings)
+	neighbors := similarities(users, c.MinOverlap, c.Neighbors)
+
+	scores := make(map[int32][]recommendation.Score, len(users))
+	for userId, ratedMovies := range users {
+		seen := make(map[int32]bool, len(ratedMovies))
+		for _, r := range ratedMovies {
+			seen[r.movieId] = true
+		}
+
+		weighted := make(map[int32]float64)
+		weights := make(map[int32]float64)
+		for _, r := range ratedMovies {
+			for _, n := range neighbors[r.movieId] {
+				if seen[n.movieId] {
+					continue
+				}
+				weighted[n.movieId] += n.similarity * r.deviation
+				weights[n.movieId] += n.similarity
+			}
+		}
+
+		predicted := make([]recommendation.Score, 0, len(weighted))
+		for movieId, w := range weighted {
+			if w <= 0 {
+				continue
+			}
+			score := min(means[userId]+w/(weights[movieId]+damping), 10)
+			predicted = append(predicted, recommendation.Score{MovieId: movieId, Score: score})
+		}
+
+		slices.SortFunc(predicted, func(a, b recommendation.Score) int {
+			return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.MovieId, b.MovieId))
+		})
+		if len(predicted) > c.PerUser {
+			predicted = predicted[:c.PerUser]
+		}
+
+		if len(predicted) > 0 {
+			scores[userId] = predicted
+		}
+	}
+
+	return scores
+}
+
+// centre groups ratings by user, as deviations from the user's mean.
+func centre(ratings []recommendation.Rating) (map[int32][]rated, map[int32]float64) {
+	if len(ratings) == 0 {
+		return nil, nil
+	}
+
+	var total float64
+	sums := make(map[int32]float64)
+	counts := make(map[int32]float64)
+	for _, r := range ratings {
+		total += float64(r.Rating)
+		sums[r.UserId] += float64(r.Rating)
+		counts[r.UserId]++
+	}
+	global := total / float64(len(ratings))
+
+	means := make(map[int32]float64, len(sums))
+	for userId, sum := range sums {
+		means[userId] = (sum + priorWeight*global) / (counts[userId] + priorWeight)
+	}
+
+	users := make(map[int32][]rated, len(sums))
+	for _, r := range ratings {
+		users[r.UserId] = append(users[r.UserId], rated{r.MovieId, float64(r.Rating) - means[r.UserId]})
+	}
+
+	return users, means
+}
+
+// similarities returns, for every movie, up to limit movies with a positive
+// similarity to it, most similar first.
+func similarities(users map[int32][]rated, minOverlap, limit int) map[int32][]neighbor {
+	pairs := make(map[[2]int32]*pair)
+	for _, ratedMovies := range users {
+		for i, a := range ratedMovies {
+			for _, b := range ratedMovies[i+1:] {
+				first, second := a, b
+				if first.movieId > second.movieId {
+					first, second = second, first
+				}
+
+				key := [2]int32{first.movieId, second.movieId}
+				p := pairs[key]
+				if p == nil {
+					p = &pair{}
+					pairs[key] = p
+				}
+
+				p.dot += first.deviation * second.deviation
+				p.normA += first.deviation * first.deviation
+				p.normB += second.deviation * second.deviation
+				p.overlap++
+			}
+		}
+	}
+
+	neighbors := make(map[int32][]neighbor)
+	for key, p := range pairs {
+		if p.overlap < minOverlap || p.normA == 0 || p.normB == 0 {
+			continue
+		}
+
+		similarity := p.dot / math.Sqrt(p.normA*p.normB)
+		if similarity <= 0 {
+			continue
+		}
+
+		neighbors[key[0]] = append(neighbors[key[0]], neighbor{key[1], similarity})
+		neighbors[key[1]] = append(neighbors[key[1]], neighbor{key[0], similarity})
+	}
+
+	for movieId, ns := range neighbors {
+		slices.SortFunc(ns, func(a, b neighbor) int {
+			return cmp.Or(cmp.Compare(b.similarity, a.similarity), cmp.Compare(a.movieId, b.movieId))
+		})
+		if len(ns) > limit {
+			neighbors[movieId] = ns[:limit]
+		}
+	}
+
+	return neighbors
+}
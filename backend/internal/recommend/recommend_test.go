@@ -0,0 +1,200 @@
+package recommend
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/config"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/recommendation"
+)
+
+func TestSimilarities(t *testing.T) {
+	tests := []struct {
+		name       string
+		users      map[int32][]rated
+		minOverlap int
+		limit      int
+		want       map[int32][]neighbor
+	}{
+		{
+			name: "alike",
+			users: map[int32][]rated{
+				1: {{1, 1}, {2, 1}},
+				2: {{1, -1}, {2, -1}},
+			},
+			minOverlap: 2,
+			limit:      10,
+			want: map[int32][]neighbor{
+				1: {{2, 1}},
+				2: {{1, 1}},
+			},
+		},
+		{
+			name: "order of the ratings doesn't matter",
+			users: map[int32][]rated{
+				1: {{2, 1}, {1, 2}},
+				2: {{1, -1}, {2, -2}},
+			},
+			minOverlap: 2,
+			limit:      10,
+			want: map[int32][]neighbor{
+				1: {{2, 4 / math.Sqrt(5*5)}},
+				2: {{1, 4 / math.Sqrt(5*5)}},
+			},
+		},
+		{
+			name: "under the overlap",
+			users: map[int32][]rated{
+				1: {{1, 1}, {2, 1}},
+				2: {{1, -1}, {2, -1}},
+			},
+			minOverlap: 3,
+			limit:      10,
+			want:       map[int32][]neighbor{},
+		},
+		{
+			name: "opposite",
+			users: map[int32][]rated{
+				1: {{1, 1}, {2, -1}},
+				2: {{1, -1}, {2, 1}},
+			},
+			minOverlap: 2,
+			limit:      10,
+			want:       map[int32][]neighbor{},
+		},
+		{
+			name: "no deviation",
+			users: map[int32][]rated{
+				1: {{1, 0}, {2, 1}},
+				2: {{1, 0}, {2, -1}},
+			},
+			minOverlap: 2,
+			limit:      10,
+			want:       map[int32][]neighbor{},
+		},
+		{
+			name: "limited, most similar first",
+			users: map[int32][]rated{
+				1: {{1, 1}, {2, 1}, {3, 1}},
+				2: {{1, -1}, {2, -1}, {3, 1}},
+				3: {{1, 1}, {2, 1}, {3, 1}},
+			},
+			minOverlap: 2,
+			limit:      1,
+			want: map[int32][]neighbor{
+				1: {{2, 1}},
+				2: {{1, 1}},
+				3: {{1, 1.0 / 3}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := similarities(tt.users, tt.minOverlap, tt.limit)
+			if len(got) != len(tt.want) {
+				t.Fatalf("similarities() = %v, want %v", got, tt.want)
+			}
+			for movieId, want := range tt.want {
+				if !neighborsEqual(got[movieId], want) {
+					t.Errorf("similarities()[%d] = %v, want %v", movieId, got[movieId], want)
+				}
+			}
+		})
+	}
+}
+
+func TestCompute(t *testing.T) {
+	// Users 1 to 3 like movies 1 and 2 alike, and movie 3 the other way.
+	community := []recommendation.Rating{
+		{UserId: 1, MovieId: 1, Rating: 9}, {UserId: 1, MovieId: 2, Rating: 9}, {UserId: 1, MovieId: 3, Rating: 2},
+		{UserId: 2, MovieId: 1, Rating: 8}, {UserId: 2, MovieId: 2, Rating: 8}, {UserId: 2, MovieId: 3, Rating: 3},
+		{UserId: 3, MovieId: 1, Rating: 2}, {UserId: 3, MovieId: 2, Rating: 3}, {UserId: 3, MovieId: 3, Rating: 9},
+	}
+	// Movie 4 is rated like movie 1.
+	sameAsFirst := slices.Concat(community, []recommendation.Rating{
+		{UserId: 1, MovieId: 4, Rating: 9}, {UserId: 2, MovieId: 4, Rating: 8}, {UserId: 3, MovieId: 4, Rating: 2},
+	})
+	c := config.Recommendations{MinOverlap: 2, Neighbors: 10, PerUser: 10}
+
+	tests := []struct {
+		name    string
+		ratings []recommendation.Rating
+		config  config.Recommendations
+		want    map[int32][]int32
+	}{
+		{
+			name:    "no ratings",
+			ratings: nil,
+			config:  c,
+			want:    map[int32][]int32{},
+		},
+		{
+			name:    "liked a movie",
+			ratings: slices.Concat(community, []recommendation.Rating{{UserId: 4, MovieId: 1, Rating: 10}}),
+			config:  c,
+			want:    map[int32][]int32{4: {2}},
+		},
+		{
+			name:    "disliked a movie",
+			ratings: slices.Concat(community, []recommendation.Rating{{UserId: 4, MovieId: 1, Rating: 1}}),
+			config:  c,
+			want:    map[int32][]int32{},
+		},
+		{
+			name:    "most similar first",
+			ratings: slices.Concat(sameAsFirst, []recommendation.Rating{{UserId: 4, MovieId: 1, Rating: 10}}),
+			config:  c,
+			want:    map[int32][]int32{4: {4, 2}},
+		},
+		{
+			name:    "limited per user",
+			ratings: slices.Concat(sameAsFirst, []recommendation.Rating{{UserId: 4, MovieId: 1, Rating: 10}}),
+			config:  config.Recommendations{MinOverlap: 2, Neighbors: 10, PerUser: 1},
+			want:    map[int32][]int32{4: {4}},
+		},
+		{
+			name:    "under the overlap",
+			ratings: slices.Concat(community, []recommendation.Rating{{UserId: 4, MovieId: 1, Rating: 10}}),
+			config:  config.Recommendations{MinOverlap: 4, Neighbors: 10, PerUser: 10},
+			want:    map[int32][]int32{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compute(tt.ratings, tt.config)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Compute() = %v, want movies %v", got, tt.want)
+			}
+
+			for userId, want := range tt.want {
+				scores := got[userId]
+				if len(scores) != len(want) {
+					t.Fatalf("Compute()[%d] = %v, want movies %v", userId, scores, want)
+				}
+				for i, s := range scores {
+					if s.MovieId != want[i] {
+						t.Errorf("Compute()[%d][%d] = movie %d, want %d", userId, i, s.MovieId, want[i])
+					}
+					if s.Score <= 0 || s.Score > 10 {
+						t.Errorf("Compute()[%d][%d] score = %v, want in (0, 10]", userId, i, s.Score)
+					}
+				}
+			}
+		})
+	}
+}
+
+func neighborsEqual(a, b []neighbor) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].movieId != b[i].movieId || math.Abs(a[i].similarity-b[i].similarity) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+package stores
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/db"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/movie"
+)
+
+// catalogStore keeps a local copy of the movie metadata needed to render a
+// movie without TMDB.
+type catalogStore struct {
+	db      *pgxpool.Pool
+	timeout time.Duration
+}
+
+func NewCatalogStore(db *pgxpool.Pool, timeout time.Duration) *catalogStore {
+	return &catalogStore{db, timeout}
+}
+
+func (s catalogStore) Save(c context.Context, m movie.Movie) error {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	genres, err := json.Marshal(m.Genres)
+	if err != nil {
+		return err
+	}
+
+	q := db.New(s.db)
+
+	return q.UpsertCatalogMovie(ctx, db.UpsertCatalogMovieParams{
+		ID:          m.Id,
+		Title:       m.Title,
+		ReleaseDate: m.ReleaseDate,
+		PosterPath:  m.PosterPath,
+		Genres:      genres,
+		Runtime:     m.Runtime,
+	})
+}
+
+func (s catalogStore) Read(c context.Context, id int32) (movie.Movie, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	result, err := q.ReadCatalogMovie(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return movie.Movie{}, NewErrNotFound(err)
+		}
+		return movie.Movie{}, err
+	}
+
+	return catalogRowToMovie(result)
+}
+
+// ReadMany returns every movie in ids found in the catalog, keyed by id.
+func (s catalogStore) ReadMany(c context.Context, ids []int32) (map[int32]movie.Movie, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	results, err := q.ReadCatalogMovies(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	movies := make(map[int32]movie.Movie, len(results))
+	for _, r := range results {
+		m, err := catalogRowToMovie(r)
+		if err != nil {
+			return nil, err
+		}
+		movies[m.Id] = m
+	}
+
+	return movies, nil
+}
+
+// ReadStale returns up to limit ids of movies last saved before before,
+// oldest first.
+func (s catalogStore) ReadStale(c context.Context, before time.Time, limit int32) ([]int32, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	return q.ReadStaleCatalogIds(ctx, db.ReadStaleCatalogIdsParams{UpdatedAt: before, Limit: limit})
+}
+
+func catalogRowToMovie(r db.Catalog) (movie.Movie, error) {
+	m := movie.Movie{
+		Id:          r.ID,
+		Title:       r.Title,
+		ReleaseDate: r.ReleaseDate,
+		PosterPath:  r.PosterPath,
+		Runtime:     r.Runtime,
+	}
+
+	if err := json.Unmarshal(r.Genres, &m.Genres); err != nil {
+		return m, err
+	}
+
+	return m, nil
+}
@@ -0,0 +1,147 @@
+package stores
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/db"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/feed"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/user"
+)
+
+type followStore struct {
+	db      *pgxpool.Pool
+	timeout time.Duration
+}
+
+func NewFollowStore(db *pgxpool.Pool, timeout time.Duration) *followStore {
+	return &followStore{db, timeout}
+}
+
+// Follow makes followerId follow followeeId, reporting whether it wasn't
+// already. Following twice is a no-op.
+func (s followStore) Follow(c context.Context, followerId, followeeId int32) (bool, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	rows, err := q.CreateFollow(ctx, db.CreateFollowParams{FollowerID: followerId, FolloweeID: followeeId})
+	return rows > 0, err
+}
+
+func (s followStore) Unfollow(c context.Context, followerId, followeeId int32) error {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	return q.DeleteFollow(ctx, db.DeleteFollowParams{FollowerID: followerId, FolloweeID: followeeId})
+}
+
+func (s followStore) ReadCounts(c context.Context, userId int32) (user.FollowCounts, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	counts, err := q.CountFollows(ctx, userId)
+	if err != nil {
+		return user.FollowCounts{}, err
+	}
+
+	return user.FollowCounts{Followers: counts.Followers, Following: counts.Following}, nil
+}
+
+// ReadFollowers returns up to limit users following userId, most recent
+// first, starting after cursor.
+func (s followStore) ReadFollowers(c context.Context, userId int32, cursor feed.Cursor, limit int32) ([]user.Follow, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	results, err := q.ReadFollowers(ctx, db.ReadFollowersParams{
+		UserID: userId, CreatedAt: cursor.CreatedAt, ID: cursor.Id, Limit: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	follows := make([]user.Follow, 0, len(results))
+	for _, r := range results {
+		follows = append(follows, user.Follow{User: userRowToUser(r.User), FollowedAt: r.CreatedAt})
+	}
+
+	return follows, nil
+}
+
+// ReadFollowing returns up to limit users followed by userId, most recent
+// first, starting after cursor.
+func (s followStore) ReadFollowing(c context.Context, userId int32, cursor feed.Cursor, limit int32) ([]user.Follow, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	results, err := q.ReadFollowing(ctx, db.ReadFollowingParams{
+		UserID: userId, CreatedAt: cursor.CreatedAt, ID: cursor.Id, Limit: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	follows := make([]user.Follow, 0, len(results))
+	for _, r := range results {
+		follows = append(follows, user.Follow{User: userRowToUser(r.User), FollowedAt: r.CreatedAt})
+	}
+
+	return follows, nil
+}
+
+// ReadFeed returns up to limit reviews and watchlist additions by users
+// userId follows, newest first, starting after cursor.
+func (s followStore) ReadFeed(c context.Context, userId int32, cursor feed.ItemCursor, limit int32) ([]feed.Item, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	reviews, err := q.ReadFolloweeReviews(ctx, db.ReadFolloweeReviewsParams{
+		FollowerID: userId,
+		CreatedAt:  cursor.CreatedAt,
+		Kind:       cursor.Kind,
+		UserID:     cursor.UserId,
+		ID:         cursor.Id,
+		Limit:      limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	watchlists, err := q.ReadFolloweeWatchlists(ctx, db.ReadFolloweeWatchlistsParams{
+		FollowerID: userId,
+		CreatedAt:  cursor.CreatedAt,
+		Kind:       cursor.Kind,
+		UserID:     cursor.UserId,
+		ID:         cursor.Id,
+		Limit:      limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]feed.Item, 0, len(reviews)+len(watchlists))
+	for _, r := range reviews {
+		review := reviewRowToReview(r.Review)
+		review.User = userRowToUser(r.User)
+		items = append(items, feed.NewReviewItem(review))
+	}
+	for _, w := range watchlists {
+		items = append(items, feed.NewWatchlistItem(userRowToUser(w.User), w.Watchlist.MovieID, w.Watchlist.CreatedAt))
+	}
+
+	feed.SortItems(items)
+	return items[:min(len(items), int(limit))], nil
+}
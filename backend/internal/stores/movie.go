@@ -2,12 +2,11 @@ package stores
 
 import (
 	"context"
-	"errors"
 	"time"
 
-	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rodrigoaraujo46/flickmeter/backend/internal/db"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/movie"
 )
 
 type movieStore struct {
@@ -15,23 +14,118 @@ type movieStore struct {
 	timeout time.Duration
 }
 
-func NewMovieStore(db *pgxpool.Pool) *movieStore {
-	return &movieStore{db, time.Second}
+func NewMovieStore(db *pgxpool.Pool, timeout time.Duration) *movieStore {
+	return &movieStore{db, timeout}
 }
 
-func (s movieStore) ReadAverageRating(c context.Context, id int32) (float64, error) {
+// ReadWeightedRatings returns the weighted rating of every movie in ids that
+// has at least one review, counting each as having minVotes more reviews
+// rated globalMean, or the mean of every rating when it is zero.
+func (s movieStore) ReadWeightedRatings(c context.Context, ids []int32, minVotes int32, globalMean float64) (map[int32]float64, error) {
 	ctx, cancel := context.WithTimeout(c, s.timeout)
 	defer cancel()
 
 	q := db.New(s.db)
 
-	movie, err := q.ReadMovie(ctx, id)
+	results, err := q.ReadWeightedRatings(ctx, db.ReadWeightedRatingsParams{
+		GlobalMean: globalMean,
+		MinVotes:   minVotes,
+		Ids:        ids,
+	})
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return 0, nil
-		}
-		return 0, err
+		return nil, err
 	}
 
-	return float64(movie.TotalRating) / float64(movie.ReviewCount), nil
+	ratings := make(map[int32]float64, len(results))
+	for _, r := range results {
+		ratings[r.ID] = r.WeightedRating
+	}
+
+	return ratings, nil
+}
+
+// ReadTopRated ranks the movies reviewed since since by their weighted
+// rating, counting each as having minVotes more reviews rated globalMean, or
+// the mean of every rating since since when it is zero.
+func (s movieStore) ReadTopRated(c context.Context, since time.Time, minVotes int32, globalMean float64, limit int32) ([]movie.ChartEntry, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	results, err := q.ReadTopRatedMovies(ctx, db.ReadTopRatedMoviesParams{
+		Since:      since,
+		GlobalMean: globalMean,
+		MinVotes:   minVotes,
+		Limit:      limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	chart := make([]movie.ChartEntry, 0, len(results))
+	for i, r := range results {
+		chart = append(chart, movie.ChartEntry{
+			Rank:           i + 1,
+			MovieId:        r.MovieID,
+			ReviewCount:    r.ReviewCount,
+			AverageRating:  r.AverageRating,
+			WeightedRating: r.WeightedRating,
+		})
+	}
+
+	return chart, nil
+}
+
+// ReadMostReviewed ranks the movies by how many reviews they got since
+// since.
+func (s movieStore) ReadMostReviewed(c context.Context, since time.Time, limit int32) ([]movie.ChartEntry, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	results, err := q.ReadMostReviewedMovies(ctx, db.ReadMostReviewedMoviesParams{Since: since, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+
+	chart := make([]movie.ChartEntry, 0, len(results))
+	for i, r := range results {
+		chart = append(chart, movie.ChartEntry{
+			Rank:          i + 1,
+			MovieId:       r.MovieID,
+			ReviewCount:   r.ReviewCount,
+			AverageRating: r.AverageRating,
+		})
+	}
+
+	return chart, nil
+}
+
+// ReadTrendingScores scores the movies with activity since p.Since, keeping
+// the best p.Limit of them.
+func (s movieStore) ReadTrendingScores(c context.Context, p movie.TrendingParams) (map[int32]float64, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	results, err := q.ReadTrendingScores(ctx, db.ReadTrendingScoresParams{
+		HalfLife:        p.HalfLife.Seconds(),
+		ReviewWeight:    p.ReviewWeight,
+		Since:           p.Since,
+		WatchlistWeight: p.WatchlistWeight,
+		Limit:           p.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[int32]float64, len(results))
+	for _, r := range results {
+		scores[r.MovieID] = r.Score
+	}
+
+	return scores, nil
 }
@@ -0,0 +1,110 @@
+package stores
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/db"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/feed"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/notification"
+)
+
+type notificationStore struct {
+	db      *pgxpool.Pool
+	timeout time.Duration
+}
+
+func NewNotificationStore(db *pgxpool.Pool, timeout time.Duration) *notificationStore {
+	return &notificationStore{db, timeout}
+}
+
+func (s notificationStore) Create(c context.Context, n notification.Notification) (notification.Notification, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	result, err := q.CreateNotification(ctx, db.CreateNotificationParams{
+		UserID:  n.UserId,
+		ActorID: n.Actor.Id,
+		Type:    n.Type,
+		Data:    n.Data,
+	})
+	if err != nil {
+		return n, err
+	}
+
+	created := notificationRowToNotification(result)
+	created.Actor = n.Actor
+	return created, nil
+}
+
+// Read returns up to limit of userId's notifications, newest first,
+// starting after cursor.
+func (s notificationStore) Read(c context.Context, userId int32, unreadOnly bool, cursor feed.Cursor, limit int32) ([]notification.Notification, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	results, err := q.ReadNotifications(ctx, db.ReadNotificationsParams{
+		UserID:     userId,
+		UnreadOnly: unreadOnly,
+		CreatedAt:  cursor.CreatedAt,
+		ID:         cursor.Id,
+		Limit:      limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	notifications := make([]notification.Notification, 0, len(results))
+	for _, r := range results {
+		n := notificationRowToNotification(r.Notification)
+		n.Actor = userRowToUser(r.User)
+		notifications = append(notifications, n)
+	}
+
+	return notifications, nil
+}
+
+func (s notificationStore) CountUnread(c context.Context, userId int32) (int64, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	return q.CountUnreadNotifications(ctx, userId)
+}
+
+// MarkRead marks userId's notifications in ids as read.
+func (s notificationStore) MarkRead(c context.Context, userId int32, ids []int32) (int64, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	return q.MarkNotificationsRead(ctx, db.MarkNotificationsReadParams{UserID: userId, Ids: ids})
+}
+
+// MarkAllRead marks every one of userId's notifications as read.
+func (s notificationStore) MarkAllRead(c context.Context, userId int32) (int64, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	return q.MarkAllNotificationsRead(ctx, userId)
+}
+
+func notificationRowToNotification(n db.Notification) notification.Notification {
+	return notification.Notification{
+		Id:        n.ID,
+		UserId:    n.UserID,
+		Type:      n.Type,
+		Data:      n.Data,
+		Read:      n.Read,
+		CreatedAt: n.CreatedAt,
+	}
+}
@@ -0,0 +1,64 @@
+package stores
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// slidingWindow keeps one sorted set member per accepted request, scored by
+// its time in milliseconds, and only admits a request while fewer than limit
+// members remain inside the window.
+var slidingWindow = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	count = count + 1
+	allowed = 1
+end
+redis.call('PEXPIRE', KEYS[1], window)
+
+local reset = window
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+
+return {allowed, limit - count, reset}
+`)
+
+type rateLimitStore struct {
+	client  redis.Client
+	timeout time.Duration
+}
+
+func NewRateLimitStore(client redis.Client, timeout time.Duration) *rateLimitStore {
+	return &rateLimitStore{client, timeout}
+}
+
+// Allow records a request for key and reports whether it fits in the window,
+// how many requests are left and when the oldest counted request expires.
+func (s rateLimitStore) Allow(c context.Context, key string, limit int, window time.Duration) (allowed bool, remaining int, reset time.Duration, err error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	now := time.Now().UnixMilli()
+	res, err := slidingWindow.Run(ctx, &s.client, []string{"ratelimit:" + key},
+		now, window.Milliseconds(), limit, strconv.FormatInt(now, 10)+"-"+uuid.NewString(),
+	).Int64Slice()
+	if err != nil {
+		return false, 0, 0, err
+	}
+
+	return res[0] == 1, int(res[1]), time.Duration(res[2]) * time.Millisecond, nil
+}
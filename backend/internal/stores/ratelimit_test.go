@@ -0,0 +1,121 @@
+package stores
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestRedis(t *testing.T) *redis.Client {
+	t.Helper()
+
+	client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestSlidingWindow(t *testing.T) {
+	type step struct {
+		now       int64 // milliseconds
+		allowed   bool
+		remaining int64
+		reset     int64 // milliseconds
+	}
+
+	tests := []struct {
+		name   string
+		limit  int
+		window int64
+		steps  []step
+	}{
+		{
+			name:   "fills and slides",
+			limit:  2,
+			window: 1000,
+			steps: []step{
+				{now: 0, allowed: true, remaining: 1, reset: 1000},
+				{now: 100, allowed: true, remaining: 0, reset: 900},
+				{now: 200, allowed: false, remaining: 0, reset: 800},
+				{now: 1000, allowed: true, remaining: 0, reset: 100},
+				{now: 1050, allowed: false, remaining: 0, reset: 50},
+				{now: 2500, allowed: true, remaining: 1, reset: 1000},
+			},
+		},
+		{
+			name:   "denied requests aren't counted",
+			limit:  1,
+			window: 1000,
+			steps: []step{
+				{now: 0, allowed: true, remaining: 0, reset: 1000},
+				{now: 500, allowed: false, remaining: 0, reset: 500},
+				{now: 900, allowed: false, remaining: 0, reset: 100},
+				{now: 1001, allowed: true, remaining: 0, reset: 1000},
+			},
+		},
+		{
+			name:   "same millisecond",
+			limit:  2,
+			window: 1000,
+			steps: []step{
+				{now: 0, allowed: true, remaining: 1, reset: 1000},
+				{now: 0, allowed: true, remaining: 0, reset: 1000},
+				{now: 0, allowed: false, remaining: 0, reset: 1000},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestRedis(t)
+			ctx := context.Background()
+
+			for i, s := range tt.steps {
+				res, err := slidingWindow.Run(ctx, client, []string{"ratelimit:test"},
+					s.now, tt.window, tt.limit, fmt.Sprintf("%d-%d", s.now, i),
+				).Int64Slice()
+				if err != nil {
+					t.Fatalf("step %d: %v", i, err)
+				}
+
+				got := step{now: s.now, allowed: res[0] == 1, remaining: res[1], reset: res[2]}
+				if got != s {
+					t.Errorf("step %d = %+v, want %+v", i, got, s)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitStoreAllow(t *testing.T) {
+	client := newTestRedis(t)
+	s := NewRateLimitStore(*client, time.Second)
+	ctx := context.Background()
+
+	tests := []struct {
+		key       string
+		allowed   bool
+		remaining int
+	}{
+		{key: "search:ip:1", allowed: true, remaining: 1},
+		{key: "search:ip:1", allowed: true, remaining: 0},
+		{key: "search:ip:1", allowed: false, remaining: 0},
+		{key: "search:ip:2", allowed: true, remaining: 1},
+	}
+
+	for i, tt := range tests {
+		allowed, remaining, reset, err := s.Allow(ctx, tt.key, 2, time.Minute)
+		if err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+		if allowed != tt.allowed || remaining != tt.remaining {
+			t.Errorf("request %d = %v, %d, want %v, %d", i, allowed, remaining, tt.allowed, tt.remaining)
+		}
+		if reset <= 0 || reset > time.Minute {
+			t.Errorf("request %d reset = %v, want within the window", i, reset)
+		}
+	}
+}
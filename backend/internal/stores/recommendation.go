@@ -0,0 +1,131 @@
+package stores
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/db"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/recommendation"
+)
+
+// recommendationBatch is how many recommendations are inserted per query.
+const recommendationBatch = 1000
+
+type recommendationStore struct {
+	db      *pgxpool.Pool
+	timeout time.Duration
+}
+
+func NewRecommendationStore(db *pgxpool.Pool, timeout time.Duration) *recommendationStore {
+	return &recommendationStore{db, timeout}
+}
+
+// ReadRatings returns the rating of every review. It isn't bound by the
+// store timeout, as it reads the whole table for a background job.
+func (s recommendationStore) ReadRatings(ctx context.Context) ([]recommendation.Rating, error) {
+	q := db.New(s.db)
+
+	results, err := q.ReadRatings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ratings := make([]recommendation.Rating, 0, len(results))
+	for _, r := range results {
+		ratings = append(ratings, recommendation.Rating{UserId: r.UserID, MovieId: r.MovieID, Rating: r.Rating})
+	}
+
+	return ratings, nil
+}
+
+// Replace swaps every user's recommendations for scores in one transaction,
+// so users never see a half written set. Like ReadRatings, it isn't bound by
+// the store timeout.
+func (s recommendationStore) Replace(ctx context.Context, scores map[int32][]recommendation.Score) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	qtx := db.New(s.db).WithTx(tx)
+
+	if err := qtx.DeleteRecommendations(ctx); err != nil {
+		return err
+	}
+
+	var batch db.CreateRecommendationsParams
+	flush := func() error {
+		if len(batch.UserIds) == 0 {
+			return nil
+		}
+		err := qtx.CreateRecommendations(ctx, batch)
+		batch = db.CreateRecommendationsParams{}
+		return err
+	}
+
+	for userId, userScores := range scores {
+		for _, score := range userScores {
+			batch.UserIds = append(batch.UserIds, userId)
+			batch.MovieIds = append(batch.MovieIds, score.MovieId)
+			batch.Scores = append(batch.Scores, score.Score)
+
+			if len(batch.UserIds) == recommendationBatch {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Read returns up to limit of userId's best recommendations, leaving out the
+// movies they reviewed or watched since they were computed.
+func (s recommendationStore) Read(c context.Context, userId, limit int32) ([]recommendation.Recommendation, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	results, err := q.ReadRecommendations(ctx, db.ReadRecommendationsParams{UserID: userId, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+
+	recommendations := make([]recommendation.Recommendation, 0, len(results))
+	for _, r := range results {
+		recommendations = append(recommendations, recommendation.Recommendation{
+			MovieId: r.MovieID, Score: r.Score, Source: recommendation.SourceLocal,
+		})
+	}
+
+	return recommendations, nil
+}
+
+// ReadSeen returns the ids of the movies userId reviewed or watched.
+func (s recommendationStore) ReadSeen(c context.Context, userId int32) ([]int32, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	return q.ReadSeenMovieIds(ctx, userId)
+}
+
+// ReadFavorites returns the ids of up to limit movies userId rated at least
+// minRating, best rated first.
+func (s recommendationStore) ReadFavorites(c context.Context, userId, minRating, limit int32) ([]int32, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	return q.ReadFavoriteMovieIds(ctx, db.ReadFavoriteMovieIdsParams{UserID: userId, Rating: minRating, Limit: limit})
+}
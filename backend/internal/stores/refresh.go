@@ -16,8 +16,8 @@ type refreshStore struct {
 	timeout time.Duration
 }
 
-func NewRefreshStore(db *pgxpool.Pool) *refreshStore {
-	return &refreshStore{db, time.Second}
+func NewRefreshStore(db *pgxpool.Pool, timeout time.Duration) *refreshStore {
+	return &refreshStore{db, timeout}
 }
 
 func (s refreshStore) Create(c context.Context, refresh refresh.Refresh) error {
@@ -56,3 +56,11 @@ func (s refreshStore) Delete(c context.Context, uuid uuid.UUID) error {
 	q := db.New(s.db)
 	return q.DeleteRefresh(ctx, uuid)
 }
+
+func (s refreshStore) DeleteCreatedBefore(c context.Context, before time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+	return q.DeleteRefreshCreatedBefore(ctx, before)
+}
@@ -8,48 +8,81 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rodrigoaraujo46/flickmeter/backend/internal/db"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/events"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/feed"
 	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/movie"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/search"
 )
 
-type reviewStore struct {
-	db      *pgxpool.Pool
-	timeout time.Duration
-}
+type (
+	Publisher interface {
+		Publish(ctx context.Context, topic, typ string, data any) error
+	}
+
+	reviewStore struct {
+		db        *pgxpool.Pool
+		timeout   time.Duration
+		publisher Publisher
+	}
+)
 
-func NewReviewStore(db *pgxpool.Pool) *reviewStore {
-	return &reviewStore{db, time.Second}
+// NewReviewStore returns a review store publishing every committed change
+// to the topic of the reviewed movie. Changes are also queued for the
+// subscribed webhooks in the same transaction.
+func NewReviewStore(db *pgxpool.Pool, timeout time.Duration, publisher Publisher) *reviewStore {
+	return &reviewStore{db, timeout, publisher}
 }
 
-func (s reviewStore) Create(c context.Context, review movie.Review) error {
+// Create saves review, returning it with its id and timestamps set.
+func (s reviewStore) Create(c context.Context, review movie.Review) (movie.Review, error) {
 	ctx, cancel := context.WithTimeout(c, s.timeout)
 	defer cancel()
 
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
-		return err
+		return review, err
 	}
 	defer func() { _ = tx.Rollback(ctx) }()
 
 	qtx := db.New(s.db).WithTx(tx)
 
-	if err := qtx.CreateReview(ctx, db.CreateReviewParams{
-		MovieID: review.MovieId,
-		UserID:  review.UserId,
-		Rating:  review.Rating,
-		Title:   review.Title,
-		Review:  review.Review,
-	}); err != nil {
-		return err
+	result, err := qtx.CreateReview(ctx, db.CreateReviewParams{
+		MovieID:  review.MovieId,
+		UserID:   review.UserId,
+		Rating:   review.Rating,
+		Title:    review.Title,
+		Review:   review.Review,
+		Language: review.Language,
+	})
+	if err != nil {
+		return review, err
 	}
 
 	if err := qtx.IncrementMovieRating(ctx, db.IncrementMovieRatingParams{
 		ID:     review.MovieId,
 		Rating: review.Rating,
 	}); err != nil {
-		return err
+		return review, err
+	}
+
+	created := reviewRowToReview(result)
+	created.User = review.User
+	if err := enqueueWebhooks(ctx, qtx, movie.EventReviewCreated, created); err != nil {
+		return review, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return review, err
 	}
 
-	return tx.Commit(ctx)
+	s.publish(c, created.MovieId, movie.EventReviewCreated, created)
+	return created, nil
+}
+
+// publish is best effort, the change is already committed and clients can
+// always refetch.
+func (s reviewStore) publish(c context.Context, movieId int32, typ string, data any) {
+	_ = s.publisher.Publish(c, events.MovieTopic(movieId), typ, data)
 }
 
 func (s reviewStore) ReadReviews(c context.Context, movieId, page int32) (movie.Reviews, error) {
@@ -74,6 +107,30 @@ func (s reviewStore) ReadReviews(c context.Context, movieId, page int32) (movie.
 	return reviews, nil
 }
 
+// ReadRecent returns up to limit reviews across every movie, newest first,
+// starting after cursor.
+func (s reviewStore) ReadRecent(c context.Context, cursor feed.Cursor, limit int32) (movie.Reviews, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+	results, err := q.ReadRecentReviews(ctx, db.ReadRecentReviewsParams{
+		CreatedAt: cursor.CreatedAt, ID: cursor.Id, Limit: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reviews := make(movie.Reviews, 0, len(results))
+	for _, r := range results {
+		review := reviewRowToReview(r.Review)
+		review.User = userRowToUser(r.User)
+		reviews = append(reviews, review)
+	}
+
+	return reviews, nil
+}
+
 func (s reviewStore) ReadUserReview(c context.Context, movieId, userId int32) (review movie.Review, err error) {
 	ctx, cancel := context.WithTimeout(c, s.timeout)
 	defer cancel()
@@ -132,6 +189,7 @@ func (s reviewStore) Update(c context.Context, review movie.Review) (movie.Revie
 	result, err := qtx.UpdateReview(ctx, db.UpdateReviewParams{
 		ID: review.Id, Title: review.Title,
 		Rating: review.Rating, Review: review.Review,
+		Language: review.Language,
 	})
 	if err != nil {
 		return review, err
@@ -144,15 +202,56 @@ func (s reviewStore) Update(c context.Context, review movie.Review) (movie.Revie
 		return review, err
 	}
 
+	updated := reviewRowToReview(result.Review)
+	updated.User = userRowToUser(result.User)
+	if err := enqueueWebhooks(ctx, qtx, movie.EventReviewUpdated, updated); err != nil {
+		return review, err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return review, err
 	}
 
-	review = reviewRowToReview(result.Review)
-	review.User = userRowToUser(result.User)
+	review = updated
+	s.publish(c, review.MovieId, movie.EventReviewUpdated, review)
 	return review, err
 }
 
+// Search ranks the reviews matching p.Query, 20 to a page.
+func (s reviewStore) Search(c context.Context, p search.ReviewParams) (search.Page[search.ReviewHit], error) {
+	const limit = 20
+
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	results, err := q.SearchReviews(ctx, db.SearchReviewsParams{
+		Languages: movie.SearchConfigs(),
+		Query:     p.Query,
+		MovieID:   p.MovieId,
+		UserID:    p.UserId,
+		MinRating: p.MinRating,
+		MaxRating: p.MaxRating,
+		Limit:     limit,
+		Offset:    (p.Page - 1) * limit,
+	})
+	if err != nil {
+		return search.Page[search.ReviewHit]{}, err
+	}
+
+	page := search.Page[search.ReviewHit]{Page: p.Page, Results: make([]search.ReviewHit, 0, len(results))}
+	for _, r := range results {
+		review := reviewRowToReview(r.Review)
+		review.User = userRowToUser(r.User)
+		page.Results = append(page.Results, search.NewReviewHit(review, r.TitleHighlight, r.Snippet, r.Rank))
+		page.TotalResults = int32(r.Total)
+	}
+	page.TotalPages = (page.TotalResults + limit - 1) / limit
+
+	return page, nil
+}
+
 func (s reviewStore) Delete(c context.Context, id int32) error {
 	ctx, cancel := context.WithTimeout(c, s.timeout)
 	defer cancel()
@@ -182,7 +281,20 @@ func (s reviewStore) Delete(c context.Context, id int32) error {
 		return err
 	}
 
-	return tx.Commit(ctx)
+	deleted := struct {
+		Id      int32 `json:"id"`
+		MovieId int32 `json:"movie_id"`
+	}{id, oldReview.Review.MovieID}
+	if err := enqueueWebhooks(ctx, qtx, movie.EventReviewDeleted, deleted); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	s.publish(c, deleted.MovieId, movie.EventReviewDeleted, deleted)
+	return nil
 }
 
 func reviewRowToReview(r db.Review) movie.Review {
@@ -195,5 +307,6 @@ func reviewRowToReview(r db.Review) movie.Review {
 		Review:    r.Review,
 		CreatedAt: r.CreatedAt,
 		UpdatedAt: r.UpdatedAt,
+		Language:  r.Language,
 	}
 }
@@ -22,8 +22,8 @@ type sessionStore struct {
 	timeout time.Duration
 }
 
-func NewSessionStore(client redis.Client) *sessionStore {
-	return &sessionStore{client, time.Second}
+func NewSessionStore(client redis.Client, timeout time.Duration) *sessionStore {
+	return &sessionStore{client, timeout}
 }
 
 func (s sessionStore) Create(c context.Context, ses session.Session) error {
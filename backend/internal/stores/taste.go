@@ -0,0 +1,69 @@
+package stores
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/db"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/user"
+)
+
+// tasteStore compares users by the ratings they gave the movies both
+// reviewed.
+type tasteStore struct {
+	db      *pgxpool.Pool
+	timeout time.Duration
+}
+
+func NewTasteStore(db *pgxpool.Pool, timeout time.Duration) *tasteStore {
+	return &tasteStore{db, timeout}
+}
+
+// ReadCompatibility compares userId's ratings to other's. The score is left
+// unset under minOverlap movies in common, or when either user gave them all
+// the same rating.
+func (s tasteStore) ReadCompatibility(c context.Context, userId int32, other user.User, minOverlap int32) (user.Compatibility, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	result, err := q.ReadCompatibility(ctx, db.ReadCompatibilityParams{UserID: userId, OtherID: other.Id})
+	if err != nil {
+		return user.Compatibility{}, err
+	}
+
+	return compatibility(other, result, minOverlap), nil
+}
+
+func compatibility(other user.User, r db.ReadCompatibilityRow, minOverlap int32) user.Compatibility {
+	if r.Overlap < int64(minOverlap) || !r.Correlated {
+		return user.Compatibility{User: other, Overlap: r.Overlap}
+	}
+
+	return user.NewCompatibility(other, r.Correlation, r.Overlap)
+}
+
+// ReadSimilar returns the limit users with the taste most alike userId's,
+// among the ones with at least minOverlap movies in common.
+func (s tasteStore) ReadSimilar(c context.Context, userId, minOverlap, limit int32) ([]user.Compatibility, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	results, err := q.ReadSimilarUsers(ctx, db.ReadSimilarUsersParams{
+		UserID: userId, MinOverlap: minOverlap, Limit: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	similar := make([]user.Compatibility, 0, len(results))
+	for _, r := range results {
+		similar = append(similar, user.NewCompatibility(userRowToUser(r.User), r.Correlation, r.Overlap))
+	}
+
+	return similar, nil
+}
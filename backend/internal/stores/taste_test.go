@@ -0,0 +1,38 @@
+package stores
+
+import (
+	"testing"
+
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/db"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/user"
+)
+
+func TestCompatibility(t *testing.T) {
+	const minOverlap = 5
+	score := func(n int) *int { return &n }
+
+	tests := []struct {
+		name      string
+		row       db.ReadCompatibilityRow
+		wantScore *int
+	}{
+		{name: "nothing in common", row: db.ReadCompatibilityRow{}},
+		{name: "under the overlap", row: db.ReadCompatibilityRow{Overlap: 4, Correlation: 1, Correlated: true}},
+		{name: "at the overlap", row: db.ReadCompatibilityRow{Overlap: 5, Correlation: 1, Correlated: true}, wantScore: score(100)},
+		{name: "over the overlap", row: db.ReadCompatibilityRow{Overlap: 9, Correlation: -1, Correlated: true}, wantScore: score(0)},
+		{name: "same ratings throughout", row: db.ReadCompatibilityRow{Overlap: 9}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := compatibility(user.User{Id: 3}, tt.row, minOverlap)
+
+			if got.User.Id != 3 || got.Overlap != tt.row.Overlap {
+				t.Errorf("compatibility() = %+v, want user 3 with overlap %d", got, tt.row.Overlap)
+			}
+			if (got.Score == nil) != (tt.wantScore == nil) || got.Score != nil && *got.Score != *tt.wantScore {
+				t.Errorf("compatibility() score = %v, want %v", got.Score, tt.wantScore)
+			}
+		})
+	}
+}
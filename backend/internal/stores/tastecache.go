@@ -0,0 +1,147 @@
+package stores
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/user"
+)
+
+// tasteCacheStore caches taste comparisons under the versions of the
+// reviews of the users they compare. Invalidating a user bumps their
+// version, so entries computed before it are never read again and expire.
+// Similar lists can also gain or lose other users whose reviews change,
+// so they're kept for the shorter similarTTL.
+type tasteCacheStore struct {
+	client     redis.Client
+	timeout    time.Duration
+	ttl        time.Duration
+	similarTTL time.Duration
+}
+
+// TasteVersion is the key a comparison missing from the cache is saved
+// under. It's read along with the miss, before computing the comparison, so
+// a comparison started before an invalidation is saved where it's never read.
+type TasteVersion struct {
+	key string
+}
+
+func NewTasteCacheStore(client redis.Client, timeout, ttl, similarTTL time.Duration) *tasteCacheStore {
+	return &tasteCacheStore{client, timeout, ttl, similarTTL}
+}
+
+func tasteVersionKey(userId int32) string {
+	return fmt.Sprintf("taste:version:%d", userId)
+}
+
+func compatibilityKey(userId, otherId int32, userVersion, otherVersion int64) string {
+	return fmt.Sprintf("taste:compatibility:%d:%d:%d:%d", userId, otherId, userVersion, otherVersion)
+}
+
+func similarKey(userId int32, version int64) string {
+	return fmt.Sprintf("taste:similar:%d:%d", userId, version)
+}
+
+func (s tasteCacheStore) ReadCompatibility(c context.Context, userId, otherId int32) (compatibility user.Compatibility, version TasteVersion, err error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	versions, err := s.versions(ctx, userId, otherId)
+	if err != nil {
+		return compatibility, version, err
+	}
+
+	version = TasteVersion{compatibilityKey(userId, otherId, versions[0], versions[1])}
+	return compatibility, version, s.read(ctx, version.key, &compatibility)
+}
+
+func (s tasteCacheStore) SaveCompatibility(c context.Context, version TasteVersion, compatibility user.Compatibility) error {
+	return s.save(c, version.key, compatibility, s.ttl)
+}
+
+func (s tasteCacheStore) ReadSimilar(c context.Context, userId int32) (similar []user.Compatibility, version TasteVersion, err error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	versions, err := s.versions(ctx, userId)
+	if err != nil {
+		return similar, version, err
+	}
+
+	version = TasteVersion{similarKey(userId, versions[0])}
+	return similar, version, s.read(ctx, version.key, &similar)
+}
+
+func (s tasteCacheStore) SaveSimilar(c context.Context, version TasteVersion, similar []user.Compatibility) error {
+	return s.save(c, version.key, similar, s.similarTTL)
+}
+
+// Invalidate outdates every cached comparison of userId, whose reviews
+// changed.
+func (s tasteCacheStore) Invalidate(c context.Context, userId int32) error {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	return s.client.Incr(ctx, tasteVersionKey(userId)).Err()
+}
+
+// versions returns the current version of every user, 0 for the ones never
+// invalidated.
+func (s tasteCacheStore) versions(ctx context.Context, userIds ...int32) ([]int64, error) {
+	keys := make([]string, 0, len(userIds))
+	for _, userId := range userIds {
+		keys = append(keys, tasteVersionKey(userId))
+	}
+
+	values, err := s.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]int64, len(values))
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+
+		if versions[i], err = strconv.ParseInt(value.(string), 10, 64); err != nil {
+			return nil, err
+		}
+	}
+
+	return versions, nil
+}
+
+func (s tasteCacheStore) read(ctx context.Context, key string, v any) error {
+	res, err := s.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return NewErrNotFound(err)
+		}
+		return err
+	}
+
+	return json.Unmarshal(res, v)
+}
+
+// save is a no-op for the zero TasteVersion, returned when the versions
+// couldn't be read.
+func (s tasteCacheStore) save(c context.Context, key string, v any, ttl time.Duration) error {
+	if key == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	value, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return s.client.Set(ctx, key, value, ttl).Err()
+}
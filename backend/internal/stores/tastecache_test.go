@@ -0,0 +1,155 @@
+package stores
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/user"
+)
+
+func TestTasteCacheKeys(t *testing.T) {
+	client := newTestRedis(t)
+	s := NewTasteCacheStore(*client, time.Second, time.Hour, time.Minute)
+	ctx := context.Background()
+
+	compatibility := func() string {
+		_, version, err := s.ReadCompatibility(ctx, 1, 2)
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("ReadCompatibility() error = %v, want ErrNotFound", err)
+		}
+		return version.key
+	}
+	similar := func() string {
+		_, version, err := s.ReadSimilar(ctx, 1)
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("ReadSimilar() error = %v, want ErrNotFound", err)
+		}
+		return version.key
+	}
+
+	tests := []struct {
+		name           string
+		invalidate     int32
+		wantCompatible string
+		wantSimilar    string
+	}{
+		{name: "never invalidated", wantCompatible: "taste:compatibility:1:2:0:0", wantSimilar: "taste:similar:1:0"},
+		{name: "user invalidated", invalidate: 1, wantCompatible: "taste:compatibility:1:2:1:0", wantSimilar: "taste:similar:1:1"},
+		{name: "other invalidated", invalidate: 2, wantCompatible: "taste:compatibility:1:2:1:1", wantSimilar: "taste:similar:1:1"},
+		{name: "unrelated invalidated", invalidate: 3, wantCompatible: "taste:compatibility:1:2:1:1", wantSimilar: "taste:similar:1:1"},
+	}
+
+	for _, tt := range tests {
+		if tt.invalidate != 0 {
+			if err := s.Invalidate(ctx, tt.invalidate); err != nil {
+				t.Fatalf("%s: Invalidate() error = %v", tt.name, err)
+			}
+		}
+
+		if got := compatibility(); got != tt.wantCompatible {
+			t.Errorf("%s: compatibility key = %q, want %q", tt.name, got, tt.wantCompatible)
+		}
+		if got := similar(); got != tt.wantSimilar {
+			t.Errorf("%s: similar key = %q, want %q", tt.name, got, tt.wantSimilar)
+		}
+	}
+}
+
+func TestTasteCacheInvalidate(t *testing.T) {
+	saved := user.Compatibility{User: user.User{Id: 2}, Overlap: 5}
+
+	tests := []struct {
+		name       string
+		invalidate []int32
+		wantHit    bool
+	}{
+		{name: "cached", wantHit: true},
+		{name: "user's reviews changed", invalidate: []int32{1}},
+		{name: "other's reviews changed", invalidate: []int32{2}},
+		{name: "unrelated reviews changed", invalidate: []int32{3}, wantHit: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestRedis(t)
+			s := NewTasteCacheStore(*client, time.Second, time.Hour, time.Minute)
+			ctx := context.Background()
+
+			_, version, _ := s.ReadCompatibility(ctx, 1, 2)
+			if err := s.SaveCompatibility(ctx, version, saved); err != nil {
+				t.Fatalf("SaveCompatibility() error = %v", err)
+			}
+
+			for _, userId := range tt.invalidate {
+				if err := s.Invalidate(ctx, userId); err != nil {
+					t.Fatalf("Invalidate() error = %v", err)
+				}
+			}
+
+			got, _, err := s.ReadCompatibility(ctx, 1, 2)
+			if tt.wantHit {
+				if err != nil || got.User.Id != saved.User.Id || got.Overlap != saved.Overlap {
+					t.Errorf("ReadCompatibility() = %+v, %v, want %+v", got, err, saved)
+				}
+				return
+			}
+			if !errors.Is(err, ErrNotFound) {
+				t.Errorf("ReadCompatibility() error = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+func TestTasteCacheSaveAfterInvalidate(t *testing.T) {
+	client := newTestRedis(t)
+	s := NewTasteCacheStore(*client, time.Second, time.Hour, time.Minute)
+	ctx := context.Background()
+
+	// A miss computed before the user's reviews changed is saved after.
+	_, version, _ := s.ReadSimilar(ctx, 1)
+	if err := s.Invalidate(ctx, 1); err != nil {
+		t.Fatalf("Invalidate() error = %v", err)
+	}
+	if err := s.SaveSimilar(ctx, version, []user.Compatibility{{User: user.User{Id: 2}}}); err != nil {
+		t.Fatalf("SaveSimilar() error = %v", err)
+	}
+
+	if _, _, err := s.ReadSimilar(ctx, 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ReadSimilar() error = %v, want the stale list ignored", err)
+	}
+}
+
+func TestTasteCacheTTLs(t *testing.T) {
+	client := newTestRedis(t)
+	s := NewTasteCacheStore(*client, time.Second, time.Hour, time.Minute)
+	ctx := context.Background()
+
+	_, compatibility, _ := s.ReadCompatibility(ctx, 1, 2)
+	_, similar, _ := s.ReadSimilar(ctx, 1)
+
+	if err := s.SaveCompatibility(ctx, compatibility, user.Compatibility{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveSimilar(ctx, similar, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveSimilar(ctx, TasteVersion{}, nil); err != nil {
+		t.Errorf("SaveSimilar() without a version error = %v, want it skipped", err)
+	}
+
+	tests := []struct {
+		key  string
+		want time.Duration
+	}{
+		{key: compatibility.key, want: time.Hour},
+		{key: similar.key, want: time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := client.TTL(ctx, tt.key).Val(); got != tt.want {
+			t.Errorf("TTL(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package stores
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// trendingStore keeps the movies trending on FlickMeter in Redis sorted
+// sets, one per period, scored by their recent activity.
+type trendingStore struct {
+	client  redis.Client
+	timeout time.Duration
+}
+
+func NewTrendingStore(client redis.Client, timeout time.Duration) *trendingStore {
+	return &trendingStore{client, timeout}
+}
+
+func trendingKey(weekly bool) string {
+	if weekly {
+		return "trending:local:week"
+	}
+	return "trending:local:day"
+}
+
+// Save replaces the period's trending movies with scores. The new set is
+// written aside and renamed over the old one, so readers never see it half
+// written. It expires after ttl, in case it stops being refreshed.
+func (s trendingStore) Save(c context.Context, weekly bool, scores map[int32]float64, ttl time.Duration) error {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	key := trendingKey(weekly)
+	if len(scores) == 0 {
+		return s.client.Del(ctx, key).Err()
+	}
+
+	members := make([]redis.Z, 0, len(scores))
+	for movieId, score := range scores {
+		members = append(members, redis.Z{Score: score, Member: movieId})
+	}
+
+	next := key + ":next"
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, next)
+		pipe.ZAdd(ctx, next, members...)
+		pipe.Expire(ctx, next, ttl)
+		pipe.Rename(ctx, next, key)
+		return nil
+	})
+
+	return err
+}
+
+// Read returns the ids of the period's limit most trending movies, most
+// trending first.
+func (s trendingStore) Read(c context.Context, weekly bool, limit int32) ([]int32, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	members, err := s.client.ZRevRange(ctx, trendingKey(weekly), 0, int64(limit)-1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int32, 0, len(members))
+	for _, m := range members {
+		id, err := strconv.ParseInt(m, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, int32(id))
+	}
+
+	return ids, nil
+}
@@ -17,8 +17,8 @@ type userStore struct {
 	timeout time.Duration
 }
 
-func NewUserStore(db *pgxpool.Pool) *userStore {
-	return &userStore{db, time.Second}
+func NewUserStore(db *pgxpool.Pool, timeout time.Duration) *userStore {
+	return &userStore{db, timeout}
 }
 
 func (s userStore) Read(c context.Context, id int32) (user.User, error) {
@@ -35,6 +35,23 @@ func (s userStore) Read(c context.Context, id int32) (user.User, error) {
 	return userRowToUser(u), nil
 }
 
+func (s userStore) ReadByUsername(c context.Context, username string) (user.User, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	u, err := q.ReadUserByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return user.User{}, NewErrNotFound(err)
+		}
+		return user.User{}, err
+	}
+
+	return userRowToUser(u), nil
+}
+
 func (s userStore) ReadOrCreate(c context.Context, u user.User) (user user.User, isNew bool, err error) {
 	ctx, cancel := context.WithTimeout(c, s.timeout)
 	defer cancel()
@@ -1,9 +1,12 @@
 package stores
 
 import (
+	"context"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/db"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/webhook"
 )
 
 type watchlistStore struct {
@@ -11,6 +14,36 @@ type watchlistStore struct {
 	timeout time.Duration
 }
 
-func NewWatchlistStore(db *pgxpool.Pool) *watchlistStore {
-	return &watchlistStore{db, time.Second}
+func NewWatchlistStore(db *pgxpool.Pool, timeout time.Duration) *watchlistStore {
+	return &watchlistStore{db, timeout}
+}
+
+// Add puts movieId on userId's watchlist, reporting whether it wasn't on it
+// already. New additions are queued for the subscribed webhooks in the same
+// transaction.
+func (s watchlistStore) Add(c context.Context, userId, movieId int32) (bool, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	qtx := db.New(s.db).WithTx(tx)
+
+	n, err := qtx.CreateWatchlistEntry(ctx, db.CreateWatchlistEntryParams{UserID: userId, MovieID: movieId})
+	if err != nil || n == 0 {
+		return false, err
+	}
+
+	if err := enqueueWebhooks(ctx, qtx, webhook.EventWatchlistAdded, struct {
+		UserId  int32 `json:"user_id"`
+		MovieId int32 `json:"movie_id"`
+	}{userId, movieId}); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit(ctx)
 }
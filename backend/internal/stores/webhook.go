@@ -0,0 +1,225 @@
+package stores
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/db"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/webhook"
+)
+
+// webhookStore keeps webhook subscriptions and the outbox of events waiting
+// to be delivered to them.
+type webhookStore struct {
+	db      *pgxpool.Pool
+	timeout time.Duration
+}
+
+func NewWebhookStore(db *pgxpool.Pool, timeout time.Duration) *webhookStore {
+	return &webhookStore{db, timeout}
+}
+
+// enqueueWebhooks queues data for every active webhook subscribed to typ. It
+// is meant to run in the transaction making the change, so an event is only
+// delivered if its change is committed.
+func enqueueWebhooks(ctx context.Context, q *db.Queries, typ string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return q.CreateWebhookOutbox(ctx, db.CreateWebhookOutboxParams{EventType: typ, Payload: payload})
+}
+
+func (s webhookStore) Create(c context.Context, w webhook.Webhook) (webhook.Webhook, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	result, err := q.CreateWebhook(ctx, db.CreateWebhookParams{Url: w.URL, Secret: w.Secret, Events: w.Events})
+	if err != nil {
+		return w, err
+	}
+
+	return webhookRowToWebhook(result), nil
+}
+
+func (s webhookStore) List(c context.Context) ([]webhook.Webhook, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	results, err := q.ListWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := make([]webhook.Webhook, 0, len(results))
+	for _, r := range results {
+		webhooks = append(webhooks, webhookRowToWebhook(r))
+	}
+
+	return webhooks, nil
+}
+
+// ReadMany returns every webhook in ids that still exists, keyed by id.
+func (s webhookStore) ReadMany(c context.Context, ids []int32) (map[int32]webhook.Webhook, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	results, err := q.ReadWebhooks(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := make(map[int32]webhook.Webhook, len(results))
+	for _, r := range results {
+		webhooks[r.ID] = webhookRowToWebhook(r)
+	}
+
+	return webhooks, nil
+}
+
+// Delete removes the webhook along with its deliveries.
+func (s webhookStore) Delete(c context.Context, id int32) error {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	n, err := q.DeleteWebhook(ctx, id)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return NewErrNotFound(errors.New("webhook not found"))
+	}
+
+	return nil
+}
+
+// Claim returns up to limit deliveries that are due, hiding them from other
+// claims for lease. Deliveries neither delivered nor failed by then are
+// claimed again.
+func (s webhookStore) Claim(c context.Context, lease time.Duration, limit int32) ([]webhook.Delivery, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	results, err := q.ClaimWebhookOutbox(ctx, db.ClaimWebhookOutboxParams{
+		LeaseUntil: time.Now().Add(lease), Limit: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return outboxRowsToDeliveries(results), nil
+}
+
+func (s webhookStore) MarkDelivered(c context.Context, id int32) error {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	return q.MarkWebhookOutboxDelivered(ctx, id)
+}
+
+// Fail records a failed attempt, retrying the delivery at next unless it
+// already had maxAttempts, in which case it is dead.
+func (s webhookStore) Fail(c context.Context, id int32, reason string, next time.Time, maxAttempts int32) error {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	return q.FailWebhookOutbox(ctx, db.FailWebhookOutboxParams{
+		LastError:     reason,
+		NextAttemptAt: next,
+		MaxAttempts:   maxAttempts,
+		ID:            id,
+	})
+}
+
+// ReadDeliveries returns up to limit of the webhook's deliveries with status,
+// newest first.
+func (s webhookStore) ReadDeliveries(c context.Context, webhookId int32, status string, limit int32) ([]webhook.Delivery, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	results, err := q.ListWebhookOutbox(ctx, db.ListWebhookOutboxParams{
+		WebhookID: webhookId, Status: status, Limit: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return outboxRowsToDeliveries(results), nil
+}
+
+// Retry queues a dead delivery again, with its attempts reset.
+func (s webhookStore) Retry(c context.Context, webhookId, id int32) error {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	n, err := q.RetryWebhookOutbox(ctx, db.RetryWebhookOutboxParams{ID: id, WebhookID: webhookId})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return NewErrNotFound(errors.New("dead delivery not found"))
+	}
+
+	return nil
+}
+
+func (s webhookStore) DeleteDeliveredBefore(c context.Context, before time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	q := db.New(s.db)
+
+	return q.DeleteDeliveredWebhookOutbox(ctx, before)
+}
+
+func webhookRowToWebhook(r db.Webhook) webhook.Webhook {
+	return webhook.Webhook{
+		Id:        r.ID,
+		URL:       r.Url,
+		Secret:    r.Secret,
+		Events:    r.Events,
+		Active:    r.Active,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func outboxRowsToDeliveries(rows []db.WebhookOutbox) []webhook.Delivery {
+	deliveries := make([]webhook.Delivery, 0, len(rows))
+	for _, r := range rows {
+		deliveries = append(deliveries, webhook.Delivery{
+			Id:            r.ID,
+			WebhookId:     r.WebhookID,
+			EventType:     r.EventType,
+			Payload:       r.Payload,
+			Status:        r.Status,
+			Attempts:      r.Attempts,
+			NextAttemptAt: r.NextAttemptAt,
+			LastError:     r.LastError,
+			CreatedAt:     r.CreatedAt,
+		})
+	}
+
+	return deliveries
+}
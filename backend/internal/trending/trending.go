@@ -0,0 +1,75 @@
+package trending
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/config"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/movie"
+)
+
+const (
+	// A review says more about a movie's buzz than adding it to a watchlist.
+	reviewWeight    = 3
+	watchlistWeight = 1
+
+	// ttlIntervals is how many refreshes a ranking outlives, so it expires
+	// if the worker stops rather than trending forever.
+	ttlIntervals = 6
+)
+
+type (
+	ScoreStore interface {
+		ReadTrendingScores(ctx context.Context, p movie.TrendingParams) (map[int32]float64, error)
+	}
+
+	Store interface {
+		Save(ctx context.Context, weekly bool, scores map[int32]float64, ttl time.Duration) error
+	}
+
+	// Worker ranks the movies trending on FlickMeter from recent community
+	// activity, for the day and for the week.
+	Worker struct {
+		scores ScoreStore
+		store  Store
+		config config.Trending
+	}
+
+	period struct {
+		weekly   bool
+		window   time.Duration
+		halfLife time.Duration
+	}
+)
+
+var periods = []period{
+	{weekly: false, window: 24 * time.Hour, halfLife: 6 * time.Hour},
+	{weekly: true, window: 7 * 24 * time.Hour, halfLife: 2 * 24 * time.Hour},
+}
+
+func NewWorker(scores ScoreStore, store Store, c config.Trending) *Worker {
+	return &Worker{scores, store, c}
+}
+
+// Refresh recomputes the ranking of every period.
+func (w *Worker) Refresh(ctx context.Context) error {
+	var errs error
+	for _, p := range periods {
+		scores, err := w.scores.ReadTrendingScores(ctx, movie.TrendingParams{
+			Since:           time.Now().Add(-p.window),
+			HalfLife:        p.halfLife,
+			ReviewWeight:    reviewWeight,
+			WatchlistWeight: watchlistWeight,
+			Limit:           int32(w.config.Size),
+		})
+		if err != nil {
+			errs = errors.Join(errs, err)
+			continue
+		}
+
+		errs = errors.Join(errs, w.store.Save(ctx, p.weekly, scores, ttlIntervals*w.config.Interval))
+	}
+
+	return errs
+}
@@ -0,0 +1,161 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/config"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/webhook"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	HeaderSignature = "X-Flickmeter-Signature"
+	HeaderTimestamp = "X-Flickmeter-Timestamp"
+	HeaderEvent     = "X-Flickmeter-Event"
+	HeaderDelivery  = "X-Flickmeter-Delivery"
+
+	backoffBase = 30 * time.Second
+	backoffMax  = 6 * time.Hour
+
+	concurrency = 8
+	// maxErrorLength bounds how much of a failed response is kept.
+	maxErrorLength = 512
+)
+
+type (
+	Store interface {
+		Claim(ctx context.Context, lease time.Duration, limit int32) ([]webhook.Delivery, error)
+		ReadMany(ctx context.Context, ids []int32) (map[int32]webhook.Webhook, error)
+		MarkDelivered(ctx context.Context, id int32) error
+		Fail(ctx context.Context, id int32, reason string, next time.Time, maxAttempts int32) error
+	}
+
+	// Worker sends the deliveries queued in the outbox to their webhooks.
+	Worker struct {
+		store  Store
+		client *http.Client
+		config config.Webhooks
+	}
+
+	// payload is the body of every request, Data being the event as queued.
+	payload struct {
+		Id        int32           `json:"id"`
+		Type      string          `json:"type"`
+		CreatedAt time.Time       `json:"created_at"`
+		Data      json.RawMessage `json:"data"`
+	}
+)
+
+func NewWorker(store Store, c config.Webhooks) *Worker {
+	return &Worker{store: store, client: &http.Client{Timeout: c.Timeout}, config: c}
+}
+
+// Deliver sends a batch of due deliveries. The batch is leased for as long
+// as sending it one at a time could take, so a worker that dies midway
+// doesn't hold it for longer.
+func (w *Worker) Deliver(ctx context.Context) error {
+	lease := time.Duration(w.config.BatchSize) * w.config.Timeout
+	deliveries, err := w.store.Claim(ctx, lease, int32(w.config.BatchSize))
+	if err != nil || len(deliveries) == 0 {
+		return err
+	}
+
+	ids := make([]int32, 0, len(deliveries))
+	for _, d := range deliveries {
+		ids = append(ids, d.WebhookId)
+	}
+
+	hooks, err := w.store.ReadMany(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	var g errgroup.Group
+	g.SetLimit(concurrency)
+	for _, d := range deliveries {
+		g.Go(func() error {
+			hook, ok := hooks[d.WebhookId]
+			if !ok {
+				// Deleted since it was claimed, its deliveries went with it.
+				return nil
+			}
+
+			if err := w.send(ctx, hook, d); err != nil {
+				next := time.Now().Add(Backoff(d.Attempts))
+				return w.store.Fail(ctx, d.Id, err.Error(), next, int32(w.config.MaxAttempts))
+			}
+
+			return w.store.MarkDelivered(ctx, d.Id)
+		})
+	}
+
+	return g.Wait()
+}
+
+func (w *Worker) send(ctx context.Context, hook webhook.Webhook, d webhook.Delivery) error {
+	if !hook.Active {
+		return fmt.Errorf("webhook %d is inactive", hook.Id)
+	}
+
+	body, err := json.Marshal(payload{Id: d.Id, Type: d.EventType, CreatedAt: d.CreatedAt, Data: d.Payload})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderSignature, "sha256="+Sign(hook.Secret, timestamp, body))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderEvent, d.EventType)
+	req.Header.Set(HeaderDelivery, strconv.Itoa(int(d.Id)))
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(res.Body, maxErrorLength))
+		return fmt.Errorf("%s: %s", res.Status, message)
+	}
+
+	return nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of "timestamp.body" keyed by
+// secret. Receivers recompute it to check a request came from us, and reject
+// old timestamps to stop replays.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff returns how long to wait before retrying a delivery that failed
+// after attempts previous attempts, with jitter.
+func Backoff(attempts int32) time.Duration {
+	backoff := backoffMax
+	if attempts < 20 {
+		backoff = min(backoffBase<<attempts, backoffMax)
+	}
+	return backoff/2 + rand.N(backoff/2+1)
+}
@@ -0,0 +1,71 @@
+package webhooks
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"type":"review.created"}`)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      []byte
+		want      string
+	}{
+		{
+			name:      "event",
+			secret:    "secret",
+			timestamp: "1700000000",
+			body:      body,
+			want:      "502960878294f1989ab5e659ea032e831ab1cc7a2426f2319bd38bfe8c9690f9",
+		},
+		{
+			name:      "other secret",
+			secret:    "other",
+			timestamp: "1700000000",
+			body:      body,
+			want:      "f8163c4c005518510f6fa9faafc32dad869fd7f253e5ebb6a94693934002bba3",
+		},
+		{
+			name:      "empty",
+			secret:    "",
+			timestamp: "0",
+			body:      nil,
+			want:      "b849d5a581847b281957065739df36df2463d1977ea8d6e1e4e6cf33fadc68c3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sign(tt.secret, tt.timestamp, tt.body); got != tt.want {
+				t.Errorf("Sign() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int32
+		max      time.Duration
+	}{
+		{attempts: 0, max: 30 * time.Second},
+		{attempts: 1, max: time.Minute},
+		{attempts: 5, max: 16 * time.Minute},
+		{attempts: 9, max: 256 * time.Minute},
+		{attempts: 10, max: backoffMax},
+		{attempts: 20, max: backoffMax},
+		{attempts: 100, max: backoffMax},
+	}
+
+	for _, tt := range tests {
+		for range 100 {
+			if got := Backoff(tt.attempts); got < tt.max/2 || got > tt.max {
+				t.Fatalf("Backoff(%d) = %v, want between %v and %v", tt.attempts, got, tt.max/2, tt.max)
+			}
+		}
+	}
+}
@@ -2,53 +2,254 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/redis/go-redis/v9"
-	"github.com/rodrigoaraujo46/assert"
 	"github.com/rodrigoaraujo46/flickmeter/backend/internal/config"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/cookies"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/events"
 	"github.com/rodrigoaraujo46/flickmeter/backend/internal/handlers"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/lifecycle"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/models/refresh"
 	"github.com/rodrigoaraujo46/flickmeter/backend/internal/movieapi"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/notify"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/recommend"
 	"github.com/rodrigoaraujo46/flickmeter/backend/internal/stores"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/trending"
+	"github.com/rodrigoaraujo46/flickmeter/backend/internal/webhooks"
 )
 
 func main() {
-	c := config.MustLoadConfig()
+	var err error
+	if len(os.Args) > 1 {
+		err = runCommand(os.Args[1:])
+	} else {
+		err = run()
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func runCommand(args []string) error {
+	switch strings.Join(args, " ") {
+	case "config check":
+		if _, err := config.Load(); err != nil {
+			return fmt.Errorf("invalid config:\n%w", err)
+		}
+		fmt.Println("config ok")
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q, usage: %s [config check]", strings.Join(args, " "), os.Args[0])
+	}
+}
+
+func run() error {
+	c, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("invalid config:\n%w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	e := echo.New()
 	e.Debug = true
+	e.IPExtractor = ipExtractor(c.TrustedProxyRanges())
 
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins: []string{"http://web:5173", "http://web:4173"}, AllowCredentials: true,
+		AllowOrigins: c.CORS.AllowOrigins, AllowCredentials: true,
 	}))
+	e.Use(middleware.SecureWithConfig(middleware.SecureConfig{
+		ContentTypeNosniff:    "nosniff",
+		XFrameOptions:         c.Security.FrameOptions,
+		HSTSMaxAge:            c.Security.HSTSMaxAge,
+		HSTSPreloadEnabled:    c.Security.HSTSPreload,
+		ContentSecurityPolicy: c.Security.ContentSecurityPolicy,
+		ReferrerPolicy:        c.Security.ReferrerPolicy,
+	}))
+
+	lc := lifecycle.New(e.Logger)
 
-	setUpHandlers(c, e)
-	e.Logger.Fatal(e.Start(fmt.Sprintf("%s:%s", c.Host, c.Port)))
+	if err := setUpHandlers(ctx, c, e, lc); err != nil {
+		return errors.Join(err, lc.Stop(context.Background()))
+	}
+
+	lc.Start(ctx)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- e.Start(fmt.Sprintf("%s:%s", c.Host, c.Port))
+	}()
+
+	var errs error
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			errs = fmt.Errorf("failed to start server: %w", err)
+		}
+	case <-ctx.Done():
+		e.Logger.Info("shutting down")
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), c.ShutdownTimeout)
+	defer cancel()
+
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		errs = errors.Join(errs, fmt.Errorf("failed to shut down server: %w", err))
+	}
+
+	return errors.Join(errs, lc.Stop(shutdownCtx))
 }
 
-func setUpHandlers(c config.Config, e *echo.Echo) {
-	psql, err := pgxpool.New(context.Background(), c.Postgres.Address)
-	assert.NoError(err, "failed to connect to postgres")
+func setUpHandlers(ctx context.Context, c config.Config, e *echo.Echo, lc *lifecycle.Lifecycle) error {
+	psql, err := pgxpool.New(ctx, c.Postgres.Address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+	lc.OnStop("postgres", func() error { psql.Close(); return nil })
 
-	ExecSchema(psql)
+	if err := psql.Ping(ctx); err != nil {
+		return fmt.Errorf("failed to ping postgres: %w", err)
+	}
+
+	if err := ExecSchema(psql); err != nil {
+		return err
+	}
 
 	redis := redis.NewClient(&redis.Options{Addr: c.Redis.Address})
+	lc.OnStop("redis", redis.Close)
 
-	userHandler := handlers.NewUserHandler(stores.NewSessionStore(*redis),
-		stores.NewRefreshStore(psql), stores.NewUserStore(psql), c.Gothic)
+	if err := redis.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("failed to ping redis: %w", err)
+	}
 
-	movieHandler := handlers.NewMovieHandler(movieapi.NewClient(c.MovieAPI),
-		stores.NewMovieStore(psql), stores.NewReviewStore(psql))
+	timeout := c.Stores.Timeout
+	cookiePolicy := cookies.NewPolicy(c.Cookie)
+	csrf := handlers.CSRF(cookiePolicy)
+	limits := handlers.NewRateLimits(stores.NewRateLimitStore(*redis, timeout), c.RateLimits)
 
-	watchlistHandler := handlers.NewWatchlistHandler(stores.NewWatchlistStore(psql))
+	refreshStore := stores.NewRefreshStore(psql, timeout)
 
-	userHandler.RegisterRoutes(e.Group("/users"), userHandler.Protection)
-	movieHandler.RegisterRoutes(e.Group("/movies"), userHandler.Protection)
-	watchlistHandler.RegisterRoutes(e.Group("/watchlists"))
+	userStore := stores.NewUserStore(psql, timeout)
+
+	userHandler := handlers.NewUserHandler(stores.NewSessionStore(*redis, timeout),
+		refreshStore, userStore, c.Gothic, cookiePolicy)
+
+	movieClient := movieapi.NewClient(c.MovieAPI)
+
+	movieStore := stores.NewMovieStore(psql, timeout)
+
+	broker := events.NewBroker(*redis, timeout, c.Events, e.Logger)
+	lc.Go("events", broker)
+	e.Server.RegisterOnShutdown(broker.Close)
+
+	reviewStore := stores.NewReviewStore(psql, timeout, broker)
+	catalogStore := stores.NewCatalogStore(psql, timeout)
+
+	tasteCache := stores.NewTasteCacheStore(*redis, timeout, c.Taste.CacheTTL, c.Taste.SimilarTTL)
+
+	trendingStore := stores.NewTrendingStore(*redis, timeout)
+
+	movieHandler := handlers.NewMovieHandler(movieClient, movieStore, catalogStore, reviewStore,
+		tasteCache, trendingStore, c.Ratings)
+	chartHandler := handlers.NewChartHandler(movieStore, catalogStore, movieClient, c.Ratings)
+	reviewHandler := handlers.NewReviewHandler(reviewStore, catalogStore, movieClient)
+	notificationStore := stores.NewNotificationStore(psql, timeout)
+	notifier := notify.NewService(notify.InApp(notificationStore), notify.Realtime(broker))
+
+	followHandler := handlers.NewFollowHandler(stores.NewFollowStore(psql, timeout),
+		userStore, notifier, catalogStore, movieClient)
+	notificationHandler := handlers.NewNotificationHandler(notificationStore)
+	eventsHandler := handlers.NewEventsHandler(broker, c.Events.Heartbeat)
+	recommendationStore := stores.NewRecommendationStore(psql, timeout)
+	tasteHandler := handlers.NewTasteHandler(stores.NewTasteStore(psql, timeout), tasteCache,
+		userStore, c.Taste)
+	recommendationHandler := handlers.NewRecommendationHandler(recommendationStore, movieClient,
+		catalogStore, movieClient)
+
+	personHandler := handlers.NewPersonHandler(movieClient)
+	genreHandler := handlers.NewGenreHandler(movieClient)
+	collectionHandler := handlers.NewCollectionHandler(movieClient, movieStore, c.Ratings)
+
+	watchlistHandler := handlers.NewWatchlistHandler(stores.NewWatchlistStore(psql, timeout))
+
+	webhookStore := stores.NewWebhookStore(psql, timeout)
+	webhookHandler := handlers.NewWebhookHandler(webhookStore)
+
+	users := e.Group("/users")
+	userHandler.RegisterRoutes(users, userHandler.Protection, csrf, limits)
+	followHandler.RegisterRoutes(users, userHandler.Protection, csrf)
+	notificationHandler.RegisterRoutes(users, userHandler.Protection, csrf)
+	recommendationHandler.RegisterRoutes(users, userHandler.Protection)
+	tasteHandler.RegisterRoutes(users, userHandler.Protection)
+	movies := e.Group("/movies")
+	movieHandler.RegisterRoutes(movies, userHandler.Authentication, userHandler.Protection, csrf, limits)
+	chartHandler.RegisterRoutes(movies)
+	eventsHandler.RegisterRoutes(movies, users, userHandler.Authentication, userHandler.Protection)
+	reviewHandler.RegisterRoutes(e.Group("/reviews"), limits)
+	personHandler.RegisterRoutes(e.Group("/people"))
+	genreHandler.RegisterRoutes(e.Group("/genres"))
+	collectionHandler.RegisterRoutes(e.Group("/collections"))
+	watchlistHandler.RegisterRoutes(e.Group("/watchlists"), userHandler.Protection, csrf)
+	webhookHandler.RegisterRoutes(e.Group("/webhooks"), userHandler.Protection, csrf,
+		handlers.Admin(c.AdminEmails))
+
+	lc.Every("image config refresh", 24*time.Hour, func(ctx context.Context) error {
+		_, err := movieClient.RefreshImageConfig(ctx)
+		return err
+	})
+
+	lc.Every("catalog refresh", time.Hour, movieHandler.RefreshCatalog)
+
+	lc.Every("webhook delivery", c.Webhooks.Interval, webhooks.NewWorker(webhookStore, c.Webhooks).Deliver)
+
+	lc.Every("webhook cleanup", time.Hour, func(ctx context.Context) error {
+		_, err := webhookStore.DeleteDeliveredBefore(ctx, time.Now().Add(-c.Webhooks.Retention))
+		return err
+	})
+
+	lc.Every("recommendations", c.Recommendations.Interval,
+		recommend.NewEngine(recommendationStore, c.Recommendations).Recompute)
+
+	lc.Every("trending", c.Trending.Interval,
+		trending.NewWorker(movieStore, trendingStore, c.Trending).Refresh)
+
+	lc.Every("refresh cleanup", time.Hour, func(ctx context.Context) error {
+		_, err := refreshStore.DeleteCreatedBefore(ctx, time.Now().Add(-refresh.MaxAge))
+		return err
+	})
+
+	return nil
+}
+
+// ipExtractor reads the client IP from X-Forwarded-For when the request comes
+// through one of the trusted proxies, and from the connection otherwise.
+func ipExtractor(trusted []*net.IPNet) echo.IPExtractor {
+	if len(trusted) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false),
+	}
+	for _, ipNet := range trusted {
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+	return echo.ExtractIPFromXFFHeader(options...)
 }
 
 func ExecSchema(db *pgxpool.Pool) error {
@@ -2,6 +2,45 @@
 SELECT * FROM movies
 WHERE id = $1;
 
+-- name: ReadMovies :many
+SELECT * FROM movies
+WHERE id = ANY(sqlc.arg(ids)::int[]);
+
+-- name: ReadWeightedRatings :many
+WITH prior AS (
+    SELECT COALESCE(NULLIF(sqlc.arg(global_mean)::float8, 0), sum(total_rating)::float8 / NULLIF(sum(review_count), 0), 0)::float8 AS mean
+    FROM movies
+)
+SELECT movies.id,
+    ((movies.total_rating + sqlc.arg(min_votes)::int * prior.mean)
+        / (movies.review_count + sqlc.arg(min_votes)::int))::float8 AS weighted_rating
+FROM movies, prior
+WHERE movies.id = ANY(sqlc.arg(ids)::int[]) AND movies.review_count > 0;
+
+-- name: UpsertCatalogMovie :exec
+INSERT INTO catalog (id, title, release_date, poster_path, genres, runtime)
+VALUES ($1, $2, $3, $4, $5, $6)
+    ON CONFLICT (id) DO UPDATE
+    SET title = EXCLUDED.title,
+    release_date = EXCLUDED.release_date,
+    poster_path = EXCLUDED.poster_path,
+    genres = EXCLUDED.genres,
+    runtime = EXCLUDED.runtime;
+
+-- name: ReadCatalogMovie :one
+SELECT * FROM catalog
+WHERE id = $1;
+
+-- name: ReadCatalogMovies :many
+SELECT * FROM catalog
+WHERE id = ANY(sqlc.arg(ids)::int[]);
+
+-- name: ReadStaleCatalogIds :many
+SELECT id FROM catalog
+WHERE updated_at < $1
+ORDER BY updated_at
+LIMIT $2;
+
 -- name: CreateRefresh :exec
 INSERT INTO refresh (id, user_id)
 VALUES ($1, $2);
@@ -16,9 +55,14 @@ WHERE r.id = $1;
 DELETE FROM refresh
 WHERE id = $1;
 
--- name: CreateReview :exec
-INSERT INTO reviews (movie_id, user_id, rating, title, review)
-VALUES ($1, $2, $3, $4, $5);
+-- name: DeleteRefreshCreatedBefore :execrows
+DELETE FROM refresh
+WHERE created_at < $1;
+
+-- name: CreateReview :one
+INSERT INTO reviews (movie_id, user_id, rating, title, review, language)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
 
 -- name: IncrementMovieRating :exec
 INSERT INTO movies (id, total_rating, review_count)
@@ -46,6 +90,14 @@ WHERE reviews.movie_id = $1
 ORDER BY reviews.updated_at DESC
 LIMIT $2 OFFSET $3;
 
+-- name: ReadRecentReviews :many
+SELECT sqlc.embed(reviews), sqlc.embed(users)
+FROM reviews
+JOIN users ON reviews.user_id = users.id
+WHERE (reviews.created_at, reviews.id) < (sqlc.arg(created_at)::timestamptz, sqlc.arg(id)::int)
+ORDER BY reviews.created_at DESC, reviews.id DESC
+LIMIT sqlc.arg('limit');
+
 -- name: ReadUserReview :one
 SELECT sqlc.embed(reviews), sqlc.embed(users)
 FROM reviews
@@ -60,11 +112,32 @@ WHERE reviews.id = $1;
 
 -- name: UpdateReview :one
 UPDATE reviews
-SET title = $2, rating = $3, review = $4
+SET title = $2, rating = $3, review = $4, language = $5
 FROM users
 WHERE reviews.id = $1
 RETURNING sqlc.embed(reviews), sqlc.embed(users);
 
+-- name: SearchReviews :many
+WITH queries AS (
+    SELECT config::regconfig AS language, websearch_to_tsquery(config::regconfig, sqlc.arg(query)) AS query
+    FROM unnest(sqlc.arg(languages)::text[]) AS config
+)
+SELECT sqlc.embed(reviews), sqlc.embed(users),
+    ts_headline(reviews.language, reviews.title, queries.query,
+        'HighlightAll=true, StartSel=<mark>, StopSel=</mark>')::text AS title_highlight,
+    ts_headline(reviews.language, reviews.review, queries.query,
+        'MaxFragments=2, MaxWords=30, MinWords=10, StartSel=<mark>, StopSel=</mark>')::text AS snippet,
+    ts_rank(reviews.search, queries.query)::float8 AS rank,
+    count(*) OVER () AS total
+FROM queries
+JOIN reviews ON reviews.language = queries.language AND reviews.search @@ queries.query
+JOIN users ON reviews.user_id = users.id
+WHERE (sqlc.arg(movie_id)::int = 0 OR reviews.movie_id = sqlc.arg(movie_id))
+    AND (sqlc.arg(user_id)::int = 0 OR reviews.user_id = sqlc.arg(user_id))
+    AND reviews.rating BETWEEN sqlc.arg(min_rating)::int AND sqlc.arg(max_rating)::int
+ORDER BY rank DESC, reviews.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
 -- name: DeleteReview :exec
 DELETE FROM reviews
 WHERE id = $1;
@@ -83,3 +156,261 @@ WHERE email = $1;
 INSERT INTO users (username, email, avatar_url)
 VALUES ($1, $2, $3)
 RETURNING *;
+
+-- name: ReadUserByUsername :one
+SELECT *
+FROM users
+WHERE username = $1;
+
+-- name: CreateFollow :execrows
+INSERT INTO follows (follower_id, followee_id)
+VALUES (sqlc.arg(follower_id), sqlc.arg(followee_id))
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteFollow :exec
+DELETE FROM follows
+WHERE follower_id = sqlc.arg(follower_id) AND followee_id = sqlc.arg(followee_id);
+
+-- name: CountFollows :one
+SELECT
+    (SELECT count(*) FROM follows WHERE followee_id = sqlc.arg(user_id)) AS followers,
+    (SELECT count(*) FROM follows WHERE follower_id = sqlc.arg(user_id)) AS following;
+
+-- name: ReadFollowers :many
+SELECT sqlc.embed(users), follows.created_at
+FROM follows
+JOIN users ON users.id = follows.follower_id
+WHERE follows.followee_id = sqlc.arg(user_id)
+    AND (follows.created_at, users.id) < (sqlc.arg(created_at)::timestamptz, sqlc.arg(id)::int)
+ORDER BY follows.created_at DESC, users.id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: ReadFollowing :many
+SELECT sqlc.embed(users), follows.created_at
+FROM follows
+JOIN users ON users.id = follows.followee_id
+WHERE follows.follower_id = sqlc.arg(user_id)
+    AND (follows.created_at, users.id) < (sqlc.arg(created_at)::timestamptz, sqlc.arg(id)::int)
+ORDER BY follows.created_at DESC, users.id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: ReadFolloweeReviews :many
+SELECT sqlc.embed(reviews), sqlc.embed(users)
+FROM reviews
+JOIN follows ON follows.followee_id = reviews.user_id
+JOIN users ON users.id = reviews.user_id
+WHERE follows.follower_id = sqlc.arg(follower_id)
+    -- 2 is feed.KindReview
+    AND (reviews.created_at, 2, reviews.user_id, reviews.id)
+        < (sqlc.arg(created_at)::timestamptz, sqlc.arg(kind)::int, sqlc.arg(user_id)::int, sqlc.arg(id)::int)
+ORDER BY reviews.created_at DESC, reviews.user_id DESC, reviews.id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: ReadFolloweeWatchlists :many
+SELECT sqlc.embed(watchlists), sqlc.embed(users)
+FROM watchlists
+JOIN follows ON follows.followee_id = watchlists.user_id
+JOIN users ON users.id = watchlists.user_id
+WHERE follows.follower_id = sqlc.arg(follower_id)
+    -- 1 is feed.KindWatchlist
+    AND (watchlists.created_at, 1, watchlists.user_id, watchlists.movie_id)
+        < (sqlc.arg(created_at)::timestamptz, sqlc.arg(kind)::int, sqlc.arg(user_id)::int, sqlc.arg(id)::int)
+ORDER BY watchlists.created_at DESC, watchlists.user_id DESC, watchlists.movie_id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: CreateNotification :one
+INSERT INTO notifications (user_id, actor_id, type, data)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: ReadNotifications :many
+SELECT sqlc.embed(notifications), sqlc.embed(users)
+FROM notifications
+JOIN users ON users.id = notifications.actor_id
+WHERE notifications.user_id = sqlc.arg(user_id)
+    AND (NOT sqlc.arg(unread_only)::bool OR NOT notifications.read)
+    AND (notifications.created_at, notifications.id) < (sqlc.arg(created_at)::timestamptz, sqlc.arg(id)::int)
+ORDER BY notifications.created_at DESC, notifications.id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: CountUnreadNotifications :one
+SELECT count(*) FROM notifications
+WHERE user_id = sqlc.arg(user_id) AND NOT read;
+
+-- name: MarkNotificationsRead :execrows
+UPDATE notifications
+SET read = TRUE
+WHERE user_id = sqlc.arg(user_id) AND id = ANY(sqlc.arg(ids)::int[]) AND NOT read;
+
+-- name: MarkAllNotificationsRead :execrows
+UPDATE notifications
+SET read = TRUE
+WHERE user_id = sqlc.arg(user_id) AND NOT read;
+
+-- name: CreateWebhook :one
+INSERT INTO webhooks (url, secret, events)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: ListWebhooks :many
+SELECT * FROM webhooks
+ORDER BY id;
+
+-- name: ReadWebhooks :many
+SELECT * FROM webhooks
+WHERE id = ANY(sqlc.arg(ids)::int[]);
+
+-- name: DeleteWebhook :execrows
+DELETE FROM webhooks
+WHERE id = $1;
+
+-- name: CreateWebhookOutbox :exec
+INSERT INTO webhook_outbox (webhook_id, event_type, payload)
+SELECT id, sqlc.arg(event_type)::text, sqlc.arg(payload)::jsonb
+FROM webhooks
+WHERE active AND sqlc.arg(event_type)::text = ANY(events);
+
+-- name: ClaimWebhookOutbox :many
+UPDATE webhook_outbox
+SET next_attempt_at = sqlc.arg(lease_until)
+WHERE id IN (
+    SELECT id FROM webhook_outbox
+    WHERE status = 'pending' AND next_attempt_at <= NOW()
+    ORDER BY next_attempt_at
+    LIMIT sqlc.arg('limit')
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkWebhookOutboxDelivered :exec
+UPDATE webhook_outbox
+SET status = 'delivered', attempts = attempts + 1, last_error = ''
+WHERE id = $1;
+
+-- name: FailWebhookOutbox :exec
+UPDATE webhook_outbox
+SET attempts = attempts + 1,
+    last_error = sqlc.arg(last_error),
+    next_attempt_at = sqlc.arg(next_attempt_at),
+    status = CASE WHEN attempts + 1 >= sqlc.arg(max_attempts)::int THEN 'dead' ELSE 'pending' END
+WHERE id = sqlc.arg(id);
+
+-- name: ListWebhookOutbox :many
+SELECT * FROM webhook_outbox
+WHERE webhook_id = $1 AND status = $2
+ORDER BY id DESC
+LIMIT $3;
+
+-- name: RetryWebhookOutbox :execrows
+UPDATE webhook_outbox
+SET status = 'pending', attempts = 0, next_attempt_at = NOW()
+WHERE id = $1 AND webhook_id = $2 AND status = 'dead';
+
+-- name: DeleteDeliveredWebhookOutbox :execrows
+DELETE FROM webhook_outbox
+WHERE status = 'delivered' AND updated_at < $1;
+
+-- name: ReadRatings :many
+SELECT user_id, movie_id, rating FROM reviews;
+
+-- name: DeleteRecommendations :exec
+DELETE FROM recommendations;
+
+-- name: CreateRecommendations :exec
+INSERT INTO recommendations (user_id, movie_id, score)
+SELECT unnest(sqlc.arg(user_ids)::int[]), unnest(sqlc.arg(movie_ids)::int[]), unnest(sqlc.arg(scores)::float8[]);
+
+-- name: ReadRecommendations :many
+SELECT movie_id, score FROM recommendations
+WHERE recommendations.user_id = $1
+    AND NOT EXISTS (
+        SELECT 1 FROM reviews
+        WHERE reviews.user_id = recommendations.user_id AND reviews.movie_id = recommendations.movie_id
+    )
+    AND NOT EXISTS (
+        SELECT 1 FROM watchlists
+        WHERE watchlists.user_id = recommendations.user_id AND watchlists.movie_id = recommendations.movie_id
+            AND watchlists.watched
+    )
+ORDER BY score DESC, movie_id
+LIMIT $2;
+
+-- name: ReadSeenMovieIds :many
+SELECT movie_id FROM reviews WHERE reviews.user_id = $1
+UNION
+SELECT movie_id FROM watchlists WHERE watchlists.user_id = $1 AND watched;
+
+-- name: ReadFavoriteMovieIds :many
+SELECT movie_id FROM reviews
+WHERE user_id = $1 AND rating >= $2
+ORDER BY rating DESC, created_at DESC
+LIMIT $3;
+
+-- name: ReadCompatibility :one
+SELECT count(*) AS overlap,
+    COALESCE(corr(mine.rating, theirs.rating), 0)::float8 AS correlation,
+    corr(mine.rating, theirs.rating) IS NOT NULL AS correlated
+FROM reviews mine
+JOIN reviews theirs ON theirs.movie_id = mine.movie_id
+WHERE mine.user_id = sqlc.arg(user_id) AND theirs.user_id = sqlc.arg(other_id);
+
+-- name: ReadSimilarUsers :many
+SELECT sqlc.embed(users), similar.overlap, similar.correlation
+FROM (
+    SELECT theirs.user_id, count(*) AS overlap, corr(mine.rating, theirs.rating)::float8 AS correlation
+    FROM reviews mine
+    JOIN reviews theirs ON theirs.movie_id = mine.movie_id AND theirs.user_id <> mine.user_id
+    WHERE mine.user_id = sqlc.arg(user_id)
+    GROUP BY theirs.user_id
+    HAVING count(*) >= sqlc.arg(min_overlap)::int AND corr(mine.rating, theirs.rating) IS NOT NULL
+) similar
+JOIN users ON users.id = similar.user_id
+ORDER BY similar.correlation DESC, similar.overlap DESC, users.id
+LIMIT sqlc.arg('limit');
+
+-- name: ReadTopRatedMovies :many
+WITH stats AS (
+    SELECT movie_id, count(*) AS review_count, avg(rating)::float8 AS average_rating
+    FROM reviews
+    WHERE created_at >= sqlc.arg(since)
+    GROUP BY movie_id
+), prior AS (
+    SELECT COALESCE(NULLIF(sqlc.arg(global_mean)::float8, 0), avg(rating), 0)::float8 AS mean
+    FROM reviews
+    WHERE created_at >= sqlc.arg(since)
+)
+SELECT stats.movie_id, stats.review_count, stats.average_rating,
+    ((stats.review_count * stats.average_rating + sqlc.arg(min_votes)::int * prior.mean)
+        / (stats.review_count + sqlc.arg(min_votes)::int))::float8 AS weighted_rating
+FROM stats, prior
+ORDER BY weighted_rating DESC, stats.review_count DESC, stats.movie_id
+LIMIT sqlc.arg('limit');
+
+-- name: ReadMostReviewedMovies :many
+SELECT movie_id, count(*) AS review_count, avg(rating)::float8 AS average_rating
+FROM reviews
+WHERE created_at >= sqlc.arg(since)
+GROUP BY movie_id
+ORDER BY review_count DESC, average_rating DESC, movie_id
+LIMIT sqlc.arg('limit');
+
+-- name: ReadTrendingScores :many
+SELECT movie_id,
+    sum(weight * exp(-ln(2) * extract(epoch FROM NOW() - created_at) / sqlc.arg(half_life)::float8))::float8 AS score
+FROM (
+    SELECT movie_id, created_at, sqlc.arg(review_weight)::float8 AS weight
+    FROM reviews
+    WHERE created_at >= sqlc.arg(since)
+    UNION ALL
+    SELECT movie_id, created_at, sqlc.arg(watchlist_weight)::float8 AS weight
+    FROM watchlists
+    WHERE created_at >= sqlc.arg(since)
+) activity
+GROUP BY movie_id
+ORDER BY score DESC, movie_id
+LIMIT sqlc.arg('limit');
+
+-- name: CreateWatchlistEntry :execrows
+INSERT INTO watchlists (user_id, movie_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
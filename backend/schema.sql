@@ -22,6 +22,17 @@ CREATE TABLE IF NOT EXISTS movies (
     updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
 );
 
+CREATE TABLE IF NOT EXISTS catalog (
+    id INT PRIMARY KEY,
+    title TEXT NOT NULL,
+    release_date TEXT NOT NULL,
+    poster_path TEXT NOT NULL,
+    genres JSONB NOT NULL DEFAULT '[]',
+    runtime INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
 CREATE TABLE IF NOT EXISTS reviews (
     id SERIAL PRIMARY KEY,
     movie_id INT NOT NULL,
@@ -34,7 +45,7 @@ CREATE TABLE IF NOT EXISTS reviews (
     updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
 );
 
-CREATE TABLE watchlists (
+CREATE TABLE IF NOT EXISTS watchlists (
     user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
     movie_id INT NOT NULL,
     watched BOOLEAN NOT NULL DEFAULT FALSE,
@@ -43,9 +54,102 @@ CREATE TABLE watchlists (
     PRIMARY KEY (user_id, movie_id)
 );
 
+CREATE TABLE IF NOT EXISTS webhooks (
+    id SERIAL PRIMARY KEY,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    events TEXT[] NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS webhook_outbox (
+    id SERIAL PRIMARY KEY,
+    webhook_id INT NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event_type TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'dead')),
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_outbox_pending
+ON webhook_outbox (next_attempt_at) WHERE status = 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_webhook_outbox_webhook_status
+ON webhook_outbox (webhook_id, status, id DESC);
+
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS language REGCONFIG NOT NULL DEFAULT 'simple';
+
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS search TSVECTOR
+GENERATED ALWAYS AS (
+    setweight(to_tsvector(language, title), 'A') ||
+    setweight(to_tsvector(language, review), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_reviews_search
+ON reviews USING GIN (search);
+
+CREATE TABLE IF NOT EXISTS follows (
+    follower_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    followee_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (follower_id, followee_id),
+    CHECK (follower_id <> followee_id)
+);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    actor_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type TEXT NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}',
+    read BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS recommendations (
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    movie_id INT NOT NULL,
+    score DOUBLE PRECISION NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, movie_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_recommendations_user_score
+ON recommendations (user_id, score DESC);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_created_at
+ON notifications (user_id, created_at DESC, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_unread
+ON notifications (user_id) WHERE NOT read;
+
+CREATE INDEX IF NOT EXISTS idx_follows_followee_created_at
+ON follows (followee_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_follows_follower_created_at
+ON follows (follower_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_watchlists_user_created_at
+ON watchlists (user_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_reviews_user_created_at
+ON reviews (user_id, created_at DESC, id DESC);
+
 CREATE INDEX IF NOT EXISTS idx_reviews_movie_updated_at
 ON reviews (movie_id, updated_at DESC);
 
+CREATE INDEX IF NOT EXISTS idx_reviews_created_at_id
+ON reviews (created_at DESC, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_catalog_updated_at
+ON catalog (updated_at);
+
 
 DO $$
 BEGIN
@@ -81,12 +185,30 @@ BEGIN
         FOR EACH ROW EXECUTE FUNCTION update_updated_at_column()';
     END IF;
 
+    IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'catalog_updated_at') THEN
+        EXECUTE 'CREATE TRIGGER catalog_updated_at
+        BEFORE UPDATE ON catalog
+        FOR EACH ROW EXECUTE FUNCTION update_updated_at_column()';
+    END IF;
+
     IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'reviews_updated_at') THEN
         EXECUTE 'CREATE TRIGGER reviews_updated_at
         BEFORE UPDATE ON reviews
         FOR EACH ROW EXECUTE FUNCTION update_updated_at_column()';
     END IF;
 
+    IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'webhooks_updated_at') THEN
+        EXECUTE 'CREATE TRIGGER webhooks_updated_at
+        BEFORE UPDATE ON webhooks
+        FOR EACH ROW EXECUTE FUNCTION update_updated_at_column()';
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'webhook_outbox_updated_at') THEN
+        EXECUTE 'CREATE TRIGGER webhook_outbox_updated_at
+        BEFORE UPDATE ON webhook_outbox
+        FOR EACH ROW EXECUTE FUNCTION update_updated_at_column()';
+    END IF;
+
     IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'watchlists_updated_at') THEN
         EXECUTE 'CREATE TRIGGER watchlists_updated_at
         BEFORE UPDATE ON watchlists
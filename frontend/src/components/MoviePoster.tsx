@@ -12,7 +12,10 @@ export default function MoviePoster({ movie }: { movie: Movie }) {
                 className={`h-full w-full rounded-lg object-cover ${
                     isLoading ? "hidden" : "block"
                 }`}
-                src={`https://image.tmdb.org/t/p/original/${movie.poster_path}`}
+                src={
+                    movie.poster_urls?.original ??
+                    `https://image.tmdb.org/t/p/original/${movie.poster_path}`
+                }
                 onLoad={() => setIsLoading(false)}
                 alt={`${movie.title} poster`}
             />
@@ -55,7 +55,7 @@ export default function VideoGallery({ movieId }: { movieId: number }) {
                 key={currentVideo?.id}
                 title={currentVideo?.name}
                 onLoad={() => setFrameLoading(false)}
-                src={`https://www.youtube.com/embed/${currentVideo?.key}?autoplay=1&mute=1`}
+                src={`${currentVideo?.embed_url}?autoplay=1&mute=1`}
                 allow="accelerometer; autoplay; clipboard-write; encrypted-media; gyroscope; picture-in-picture"
                 allowFullScreen
             />
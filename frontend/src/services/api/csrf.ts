@@ -0,0 +1,42 @@
+let token: Promise<string> | null = null;
+
+async function fetchCSRFToken(): Promise<string> {
+    const res = await fetch("/api/users/csrf", { credentials: "include" });
+    if (!res.ok) {
+        throw new Error(`Unexpected response: ${res.status}`);
+    }
+
+    return ((await res.json()) as { token: string }).token;
+}
+
+async function csrfToken(): Promise<string> {
+    token ??= fetchCSRFToken().catch((error) => {
+        token = null;
+        throw error;
+    });
+
+    return token;
+}
+
+// csrfFetch sends a state changing request with the CSRF token. The token
+// cookie expires, so a 403 drops the cached token and retries once.
+async function csrfFetch(
+    input: string,
+    init: RequestInit = {},
+): Promise<Response> {
+    const send = async () => {
+        const headers = new Headers(init.headers);
+        headers.set("X-CSRF-Token", await csrfToken());
+        return fetch(input, { ...init, headers });
+    };
+
+    const res = await send();
+    if (res.status !== 403) {
+        return res;
+    }
+
+    token = null;
+    return send();
+}
+
+export { csrfFetch };
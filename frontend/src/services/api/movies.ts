@@ -1,5 +1,9 @@
+import { csrfFetch } from "./csrf";
 import type { User } from "./users";
 
+// Maps TMDB size names, like w342 or original, to full image URLs.
+type ImageURLs = Record<string, string>;
+
 interface Genre {
     id: number;
     name: string;
@@ -8,6 +12,7 @@ interface Genre {
 interface ProductionCompany {
     id: number;
     logo_path: string;
+    logo_urls?: ImageURLs;
     name: string;
     origin_country: string;
 }
@@ -27,7 +32,9 @@ interface BelongsToCollection {
     id: number;
     name: string;
     poster_path: string;
+    poster_urls?: ImageURLs;
     backdrop_path: string;
+    backdrop_urls?: ImageURLs;
 }
 
 interface Video {
@@ -41,6 +48,8 @@ interface Video {
     type: string;
     official: boolean; // defaults to true
     published_at: string;
+    embed_url?: string;
+    thumbnail_url?: string;
 }
 
 interface Review {
@@ -58,6 +67,7 @@ interface Review {
 interface Movie {
     adult: boolean;
     backdrop_path: string;
+    backdrop_urls?: ImageURLs;
     belongs_to_collection?: BelongsToCollection;
     budget: number;
     genres?: Genre[];
@@ -69,6 +79,7 @@ interface Movie {
     overview: string;
     popularity: number;
     poster_path: string;
+    poster_urls?: ImageURLs;
     production_companies?: ProductionCompany[];
     production_countries?: ProductionCountry[];
     release_date: string;
@@ -95,15 +106,24 @@ async function fetchTrendingMovies(weekly: boolean): Promise<Movie[]> {
     return (await res.json()) as Movie[];
 }
 
+interface Page<T> {
+    page: number;
+    results: T[];
+    total_pages: number;
+    total_results: number;
+}
+
 async function searchMovies(query: string): Promise<Movie[]> {
-    const res = await fetch(`/api/movies/search?query=${query}`);
+    const res = await fetch(
+        `/api/movies/search?query=${encodeURIComponent(query)}`,
+    );
     if (!res.ok) {
         const error = await res.json();
         error.cause = res.status;
         throw error;
     }
 
-    return (await res.json()) as Movie[];
+    return ((await res.json()) as Page<Movie>).results;
 }
 
 async function fetchVideos(id: number): Promise<Video[]> {
@@ -163,7 +183,7 @@ async function saveReview(movieId: number, reviewId: number, review: Review) {
             ? `/api/movies/${movieId}/reviews`
             : `/api/movies/${movieId}/reviews/${reviewId}`;
 
-    const res = await fetch(url, {
+    const res = await csrfFetch(url, {
         method: method,
         headers: { "Content-Type": "application/json" },
         body: JSON.stringify(review),
@@ -173,11 +193,9 @@ async function saveReview(movieId: number, reviewId: number, review: Review) {
 }
 
 async function deleteReview(review: Review) {
-    const res = await fetch(
+    const res = await csrfFetch(
         `/api/movies/${review.movie_id}/reviews/${review.id}`,
-        {
-            method: "DELETE",
-        },
+        { method: "DELETE" },
     );
 
     if (!res.ok) throw new Error("Failed to delete review");
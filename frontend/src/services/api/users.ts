@@ -1,3 +1,5 @@
+import { csrfFetch } from "./csrf";
+
 type User = {
     id: number;
     username: string;
@@ -20,7 +22,7 @@ async function fetchCurrentUser(): Promise<User | null> {
 }
 
 async function logout(): Promise<boolean> {
-    const res = await fetch("/api/users/logout", {
+    const res = await csrfFetch("/api/users/logout", {
         method: "POST",
         credentials: "include",
     });
@@ -16,6 +16,7 @@ export default defineConfig({
             "/api": {
                 target: "http://server:1323",
                 changeOrigin: true,
+                xfwd: true,
                 rewrite: (path) => path.replace(/^\/api/, ""),
             },
         },